@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// TokenCredentials sends a bearer access token with every call. Set it as
+// Config.Credentials.
+type TokenCredentials struct {
+	// Token returns the access token to send, e.g. from a cache that
+	// refreshes it before it expires
+	Token func(ctx context.Context) (string, error)
+	// AllowInsecure permits sending the token over connections without TLS,
+	// which should only be used for local development
+	AllowInsecure bool
+}
+
+// StaticToken returns credentials that always send token
+func StaticToken(token string) *TokenCredentials {
+	return &TokenCredentials{
+		Token: func(context.Context) (string, error) {
+			return token, nil
+		},
+	}
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (t *TokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := t.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+	if token == "" {
+		return nil, nil
+	}
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials
+func (t *TokenCredentials) RequireTransportSecurity() bool {
+	return !t.AllowInsecure
+}
+
+var _ credentials.PerRPCCredentials = (*TokenCredentials)(nil)
@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+)
+
+// Sentinel errors matched with errors.Is against errors returned by
+// UserServiceClient methods
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrInvalidArgument    = errors.New("invalid argument")
+	ErrUnauthenticated    = errors.New("unauthenticated")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrFailedPrecondition = errors.New("failed precondition")
+	ErrAborted            = errors.New("aborted")
+	ErrOutOfRange         = errors.New("out of range")
+	ErrResourceExhausted  = errors.New("resource exhausted")
+	ErrUnavailable        = errors.New("service unavailable")
+	ErrDeadlineExceeded   = errors.New("deadline exceeded")
+	ErrCanceled           = errors.New("canceled")
+	ErrInternal           = errors.New("internal error")
+	ErrUnimplemented      = errors.New("unimplemented")
+	ErrUnknown            = errors.New("unknown error")
+	ErrDataLoss           = errors.New("data loss")
+)
+
+// codeErrors maps gRPC status codes to the sentinel errors above
+var codeErrors = map[codes.Code]error{
+	codes.NotFound:           ErrNotFound,
+	codes.AlreadyExists:      ErrAlreadyExists,
+	codes.InvalidArgument:    ErrInvalidArgument,
+	codes.Unauthenticated:    ErrUnauthenticated,
+	codes.PermissionDenied:   ErrPermissionDenied,
+	codes.FailedPrecondition: ErrFailedPrecondition,
+	codes.Aborted:            ErrAborted,
+	codes.OutOfRange:         ErrOutOfRange,
+	codes.ResourceExhausted:  ErrResourceExhausted,
+	codes.Unavailable:        ErrUnavailable,
+	codes.DeadlineExceeded:   ErrDeadlineExceeded,
+	codes.Canceled:           ErrCanceled,
+	codes.Internal:           ErrInternal,
+	codes.Unimplemented:      ErrUnimplemented,
+	codes.Unknown:            ErrUnknown,
+	codes.DataLoss:           ErrDataLoss,
+}
+
+// Error is returned by UserServiceClient methods when the call fails with a
+// gRPC status. It matches the sentinel error for its code with errors.Is,
+// exposes field violations as a *ValidationError through errors.As, and
+// keeps the original status available to status.FromError.
+type Error struct {
+	Code    codes.Code
+	Message string
+	// Reason, Domain and Metadata come from the google.rpc.ErrorInfo
+	// detail, if any
+	Reason   string
+	Domain   string
+	Metadata map[string]string
+
+	status     *status.Status
+	validation *ValidationError
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("user service: %s: %s", e.Code, e.Message)
+}
+
+// GRPCStatus returns the original status so that status.FromError and
+// status.Code keep working on wrapped errors
+func (e *Error) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// Unwrap exposes the sentinel error for the status code and, for invalid
+// arguments with field violations, the corresponding *ValidationError
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if sentinel, ok := codeErrors[e.Code]; ok {
+		errs = append(errs, sentinel)
+	}
+	if e.validation != nil {
+		errs = append(errs, e.validation)
+	}
+	return errs
+}
+
+// FieldViolation describes a single invalid request field
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError lists the request fields rejected by the server
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = fmt.Sprintf("%s: %s", v.Field, v.Description)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Is makes errors.Is(err, ErrInvalidArgument) true for every ValidationError
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidArgument
+}
+
+// convertError converts a gRPC status error into an *Error. Errors that do
+// not carry a status are returned unchanged.
+func convertError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var converted *Error
+	if errors.As(err, &converted) {
+		return err
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	result := &Error{
+		Code:    st.Code(),
+		Message: st.Message(),
+		status:  st,
+	}
+
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			result.Reason = d.Reason
+			result.Domain = d.Domain
+			result.Metadata = d.Metadata
+		case *errdetails.BadRequest:
+			if len(d.FieldViolations) == 0 {
+				continue
+			}
+			validation := &ValidationError{}
+			for _, v := range d.FieldViolations {
+				validation.Violations = append(validation.Violations, FieldViolation{
+					Field:       v.Field,
+					Description: v.Description,
+				})
+			}
+			result.validation = validation
+		}
+	}
+
+	return result
+}
+
+// CurrentETag returns the user's current etag reported by a conditional
+// write that failed with ErrAborted because the user was modified
+// concurrently, or "" if err carries none
+func CurrentETag(err error) string {
+	var e *Error
+	if !errors.As(err, &e) {
+		return ""
+	}
+	return e.Metadata[models.MetadataCurrentETag]
+}
+
+// errorUnaryInterceptor converts status errors from unary calls into *Error
+func errorUnaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return convertError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// errorStreamInterceptor converts status errors from streaming calls into *Error
+func errorStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &errorClientStream{ClientStream: stream}, nil
+}
+
+// errorClientStream converts status errors returned while using a stream
+type errorClientStream struct {
+	grpc.ClientStream
+}
+
+func (s *errorClientStream) SendMsg(m any) error {
+	return convertError(s.ClientStream.SendMsg(m))
+}
+
+func (s *errorClientStream) RecvMsg(m any) error {
+	return convertError(s.ClientStream.RecvMsg(m))
+}
@@ -0,0 +1,226 @@
+package client
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// ExportFormat selects how ExportUsersTo writes users
+type ExportFormat int
+
+const (
+	// ExportJSONL writes one protobuf-JSON encoded user per line
+	ExportJSONL ExportFormat = iota
+	// ExportCSV writes a header row followed by one row per user. Text
+	// cells that a spreadsheet would run as a formula are prefixed with "'".
+	ExportCSV
+)
+
+// csvHeader lists the columns written by ExportCSV
+var csvHeader = []string{"id", "email", "first_name", "last_name", "role", "rating", "created_at", "updated_at", "deleted_at"}
+
+// ExportResult summarizes an ExportUsersTo run. When the export fails,
+// ResumeToken can be passed in a new request to continue where it stopped.
+type ExportResult struct {
+	Count       int64
+	ResumeToken string
+}
+
+// ExportUsers opens a stream of every user matching the request. Unlike
+// the unary methods it does not apply Config.Timeout, since exports may run
+// for a long time; use ctx to bound it.
+func (c *UserServiceClient) ExportUsers(ctx context.Context, req *pb.ExportUsersRequest) (pb.UserService_ExportUsersClient, error) {
+	return c.client.ExportUsers(ctx, req)
+}
+
+// ExportUsersTo writes every user matching the request to w in the given
+// format. If the stream breaks with ErrUnavailable it is resumed from the
+// last received user, up to Config.MaxRetries times in a row.
+func (c *UserServiceClient) ExportUsersTo(ctx context.Context, req *pb.ExportUsersRequest, w io.Writer, format ExportFormat) (*ExportResult, error) {
+	writer, err := newUserWriter(w, format)
+	if err != nil {
+		return nil, err
+	}
+
+	req = proto.Clone(req).(*pb.ExportUsersRequest)
+	result := &ExportResult{ResumeToken: req.ResumeToken}
+	failures := 0
+
+	for {
+		count := result.Count
+		err := c.exportOnce(ctx, req, writer, result)
+		if result.Count > count {
+			failures = 0
+		}
+		if err == nil {
+			return result, writer.Flush()
+		}
+		if !errors.Is(err, ErrUnavailable) || failures >= c.config.MaxRetries {
+			if flushErr := writer.Flush(); flushErr != nil {
+				return result, errors.Join(err, flushErr)
+			}
+			return result, err
+		}
+
+		if err := sleepContext(ctx, c.resumeBackoff(failures)); err != nil {
+			return result, err
+		}
+		failures++
+		req.ResumeToken = result.ResumeToken
+	}
+}
+
+// exportOnce consumes a single export stream, recording progress in result
+func (c *UserServiceClient) exportOnce(ctx context.Context, req *pb.ExportUsersRequest, writer userWriter, result *ExportResult) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.ExportUsers(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := writer.Write(resp.User); err != nil {
+			return fmt.Errorf("failed to write exported user: %w", err)
+		}
+		result.Count++
+		result.ResumeToken = resp.ResumeToken
+	}
+}
+
+// resumeBackoff returns how long to wait before resuming a stream after the
+// given number of consecutive failures
+func (c *UserServiceClient) resumeBackoff(failures int) time.Duration {
+	policy := c.config.Retry
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+
+	backoff := float64(policy.InitialBackoff)
+	for i := 0; i < failures; i++ {
+		backoff *= policy.BackoffMultiplier
+	}
+	return min(time.Duration(backoff), policy.MaxBackoff)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// userWriter writes exported users in a particular format
+type userWriter interface {
+	Write(user *pb.User) error
+	Flush() error
+}
+
+func newUserWriter(w io.Writer, format ExportFormat) (userWriter, error) {
+	switch format {
+	case ExportJSONL:
+		return &jsonlUserWriter{w: w}, nil
+	case ExportCSV:
+		return &csvUserWriter{w: csv.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %d", format)
+	}
+}
+
+type jsonlUserWriter struct {
+	w io.Writer
+}
+
+func (j *jsonlUserWriter) Write(user *pb.User) error {
+	line, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(user)
+	if err != nil {
+		return err
+	}
+	_, err = j.w.Write(append(line, '\n'))
+	return err
+}
+
+func (j *jsonlUserWriter) Flush() error {
+	return nil
+}
+
+type csvUserWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (c *csvUserWriter) Write(user *pb.User) error {
+	if !c.wroteHeader {
+		if err := c.w.Write(csvHeader); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+
+	return c.w.Write([]string{
+		escapeCSVFormula(user.Id),
+		escapeCSVFormula(user.Email),
+		escapeCSVFormula(user.FirstName),
+		escapeCSVFormula(user.LastName),
+		user.Role.String(),
+		strconv.FormatInt(int64(user.Rating), 10),
+		formatTimestamp(user.CreatedAt),
+		formatTimestamp(user.UpdatedAt),
+		formatTimestamp(user.DeletedAt),
+	})
+}
+
+func (c *csvUserWriter) Flush() error {
+	if !c.wroteHeader {
+		if err := c.w.Write(csvHeader); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// escapeCSVFormula prefixes cells that spreadsheets would evaluate as a
+// formula with a single quote, so that a name like "=HYPERLINK(...)" is
+// shown as text rather than run when the export is opened
+func escapeCSVFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// formatTimestamp formats ts as RFC 3339, or returns "" when it is unset
+func formatTimestamp(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.AsTime().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// exportUserServer streams users, breaking the first stream after breakAfter
+// users, or every stream after one user with breakEach
+type exportUserServer struct {
+	pb.UnimplementedUserServiceServer
+	users      []*pb.User
+	breakAfter int
+	breakEach  bool
+	streams    int
+}
+
+func (s *exportUserServer) ExportUsers(req *pb.ExportUsersRequest, stream grpc.ServerStreamingServer[pb.ExportUsersResponse]) error {
+	s.streams++
+
+	start := 0
+	if req.ResumeToken != "" {
+		start, _ = strconv.Atoi(req.ResumeToken)
+	}
+	for i := start; i < len(s.users); i++ {
+		if s.streams == 1 && i == s.breakAfter || s.breakEach && i > start {
+			return status.Error(codes.Unavailable, "connection lost")
+		}
+		if err := stream.Send(&pb.ExportUsersResponse{User: s.users[i], ResumeToken: strconv.Itoa(i + 1)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportFixtureUsers() []*pb.User {
+	created := timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	return []*pb.User{
+		{Id: "1", Email: "john@example.com", FirstName: "John", LastName: "Doe", Role: pb.Role_ROLE_USER, CreatedAt: created},
+		{Id: "2", Email: "jane@example.com", FirstName: "Jane", LastName: "Roe, Jr.", Role: pb.Role_ROLE_ADMIN, Rating: 7},
+	}
+}
+
+func TestUserServiceClient_ExportUsersTo_JSONLResumes(t *testing.T) {
+	srv := &exportUserServer{users: exportFixtureUsers(), breakAfter: 1}
+	client := newTestClient(t, srv, fastRetries(3, false))
+
+	var buf bytes.Buffer
+	result, err := client.ExportUsersTo(context.Background(), &pb.ExportUsersRequest{}, &buf, ExportJSONL)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Count)
+	assert.Equal(t, "2", result.ResumeToken)
+	assert.Equal(t, 2, srv.streams)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"first_name":"John"`)
+	assert.Contains(t, lines[1], `"id":"2"`)
+}
+
+func TestUserServiceClient_ExportUsersTo_CSV(t *testing.T) {
+	client := newTestClient(t, &exportUserServer{users: exportFixtureUsers(), breakAfter: -1})
+
+	var buf bytes.Buffer
+	result, err := client.ExportUsersTo(context.Background(), &pb.ExportUsersRequest{}, &buf, ExportCSV)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Count)
+	assert.Equal(t, strings.Join([]string{
+		"id,email,first_name,last_name,role,rating,created_at,updated_at,deleted_at",
+		"1,john@example.com,John,Doe,ROLE_USER,0,2024-01-02T03:04:05Z,,",
+		`2,jane@example.com,Jane,"Roe, Jr.",ROLE_ADMIN,7,,,`,
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestUserServiceClient_ExportUsersTo_CSVEscapesFormulas(t *testing.T) {
+	users := []*pb.User{
+		{Id: "1", Email: "@evil.example.com", FirstName: `=HYPERLINK("http://evil.example.com","x")`, LastName: "+1", Rating: -2},
+		{Id: "2", Email: "a@example.com", FirstName: "-Jo", LastName: "\tTab"},
+		{Id: "3", Email: "b@example.com", FirstName: "\rCR", LastName: "Doe=Roe"},
+	}
+	client := newTestClient(t, &exportUserServer{users: users, breakAfter: -1})
+
+	var buf bytes.Buffer
+	_, err := client.ExportUsersTo(context.Background(), &pb.ExportUsersRequest{}, &buf, ExportCSV)
+
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"id,email,first_name,last_name,role,rating,created_at,updated_at,deleted_at",
+		`1,'@evil.example.com,"'=HYPERLINK(""http://evil.example.com"",""x"")",'+1,ROLE_UNSPECIFIED,-2,,,`,
+		"2,a@example.com,'-Jo,'\tTab,ROLE_UNSPECIFIED,0,,,",
+		"3,b@example.com,\"'\rCR\",Doe=Roe,ROLE_UNSPECIFIED,0,,,",
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestUserServiceClient_ExportUsersTo_GivesUp(t *testing.T) {
+	srv := &exportUserServer{users: exportFixtureUsers(), breakAfter: 1}
+	client := newTestClient(t, srv, fastRetries(0, false))
+
+	var buf bytes.Buffer
+	result, err := client.ExportUsersTo(context.Background(), &pb.ExportUsersRequest{}, &buf, ExportJSONL)
+
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, int64(1), result.Count)
+	assert.Equal(t, "1", result.ResumeToken)
+}
+
+func TestUserServiceClient_ExportUsersTo_ResetsFailuresOnProgress(t *testing.T) {
+	users := append(exportFixtureUsers(), &pb.User{Id: "3", Email: "joe@example.com"})
+	srv := &exportUserServer{users: users, breakAfter: -1, breakEach: true}
+	client := newTestClient(t, srv, fastRetries(1, false))
+
+	var buf bytes.Buffer
+	result, err := client.ExportUsersTo(context.Background(), &pb.ExportUsersRequest{}, &buf, ExportJSONL)
+
+	// Two failures in total, but never two in a row
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.Count)
+	assert.Equal(t, 3, srv.streams)
+}
@@ -0,0 +1,265 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// importColumns are the CSV columns ImportUsersFrom requires; other columns
+// are ignored, so files written by ExportUsersTo only need a password column
+var importColumns = []string{"email", "password", "first_name", "last_name"}
+
+// ImportFailure describes a record rejected by the server
+type ImportFailure struct {
+	// Record is the position of the record in the input, starting at 1 and
+	// not counting the CSV header
+	Record int64
+	Email  string
+	// Err is an *Error that matches the same sentinels CreateUser would
+	// return, e.g. ErrAlreadyExists or a *ValidationError
+	Err error
+}
+
+// ImportResult summarizes an ImportUsersFrom run
+type ImportResult struct {
+	// Succeeded counts the users created, or found valid in a dry run
+	Succeeded int64
+	Failures  []ImportFailure
+}
+
+// ImportUsers opens an import stream. Send the records and read the
+// per-record results concurrently: the server answers each record as soon
+// as it is processed. Like ExportUsers it does not apply Config.Timeout.
+func (c *UserServiceClient) ImportUsers(ctx context.Context) (pb.UserService_ImportUsersClient, error) {
+	return c.client.ImportUsers(ctx)
+}
+
+// ImportUsersFrom creates a user for every record read from r, given as
+// JSON Lines of CreateUserRequest or as CSV with a header row naming at
+// least the email, password, first_name and last_name columns. Rejected
+// records are reported in the result and do not stop the import; a
+// malformed input or a broken stream does, after which the result covers
+// the records answered so far. With dryRun the records are only validated.
+func (c *UserServiceClient) ImportUsersFrom(ctx context.Context, r io.Reader, format ExportFormat, dryRun bool) (*ImportResult, error) {
+	reader, err := newUserReader(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.ImportUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	var emails []string
+
+	// Records are sent from a separate goroutine so that neither side's
+	// flow-control window can fill up while the other waits
+	readErr := make(chan error, 1)
+	go func() {
+		err := sendImportRecords(stream, reader, dryRun, func(email string) {
+			mu.Lock()
+			emails = append(emails, email)
+			mu.Unlock()
+		})
+		// Report the error before canceling, so that it is already there
+		// when Recv fails with the resulting cancellation
+		readErr <- err
+		if err != nil {
+			cancel()
+		}
+	}()
+
+	result := &ImportResult{}
+	var recvErr error
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			recvErr = err
+			break
+		}
+
+		if resp.Error == nil {
+			result.Succeeded++
+			continue
+		}
+
+		mu.Lock()
+		var email string
+		if resp.Index >= 0 && resp.Index < int64(len(emails)) {
+			email = emails[resp.Index]
+		}
+		mu.Unlock()
+
+		result.Failures = append(result.Failures, ImportFailure{
+			Record: resp.Index + 1,
+			Email:  email,
+			Err:    convertImportError(resp.Error),
+		})
+	}
+
+	if recvErr == nil {
+		return result, <-readErr
+	}
+
+	// Do not wait for the sender, which may be blocked reading r
+	select {
+	case err := <-readErr:
+		if err != nil {
+			return result, err
+		}
+	default:
+	}
+	return result, recvErr
+}
+
+// sendImportRecords sends every record read from reader on stream, calling
+// sent with the email of each one, and closes the sending side at the end.
+// Send errors are not returned, since Recv reports their cause.
+func sendImportRecords(stream pb.UserService_ImportUsersClient, reader userReader, dryRun bool, sent func(email string)) error {
+	for record := int64(1); ; record++ {
+		user, err := reader.Read()
+		if err == io.EOF {
+			_ = stream.CloseSend()
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read record %d: %w", record, err)
+		}
+
+		sent(user.Email)
+		if err := stream.Send(&pb.ImportUsersRequest{DryRun: dryRun && record == 1, User: user}); err != nil {
+			return nil
+		}
+	}
+}
+
+// convertImportError converts a per-record error into an *Error
+func convertImportError(e *pb.ImportError) error {
+	code := codes.Code(e.Code)
+	result := &Error{
+		Code:    code,
+		Message: e.Message,
+		Reason:  e.Reason,
+		status:  status.New(code, e.Message),
+	}
+	if len(e.FieldViolations) > 0 {
+		validation := &ValidationError{}
+		for _, v := range e.FieldViolations {
+			validation.Violations = append(validation.Violations, FieldViolation{
+				Field:       v.Field,
+				Description: v.Description,
+			})
+		}
+		result.validation = validation
+	}
+	return result
+}
+
+// userReader reads create requests in a particular format, returning
+// io.EOF after the last one
+type userReader interface {
+	Read() (*pb.CreateUserRequest, error)
+}
+
+func newUserReader(r io.Reader, format ExportFormat) (userReader, error) {
+	switch format {
+	case ExportJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, 1<<20)
+		return &jsonlUserReader{scanner: scanner}, nil
+	case ExportCSV:
+		return &csvUserReader{r: csv.NewReader(r)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported import format %d", format)
+	}
+}
+
+type jsonlUserReader struct {
+	scanner *bufio.Scanner
+}
+
+func (j *jsonlUserReader) Read() (*pb.CreateUserRequest, error) {
+	for j.scanner.Scan() {
+		line := bytes.TrimSpace(j.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		user := &pb.CreateUserRequest{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(line, user); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+	if err := j.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+type csvUserReader struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func (c *csvUserReader) Read() (*pb.CreateUserRequest, error) {
+	if c.columns == nil {
+		if err := c.readHeader(); err != nil {
+			return nil, err
+		}
+	}
+
+	row, err := c.r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateUserRequest{
+		Email:     row[c.columns["email"]],
+		Password:  row[c.columns["password"]],
+		FirstName: row[c.columns["first_name"]],
+		LastName:  row[c.columns["last_name"]],
+	}, nil
+}
+
+func (c *csvUserReader) readHeader() error {
+	header, err := c.r.Read()
+	if err == io.EOF {
+		return errors.New("missing CSV header")
+	}
+	if err != nil {
+		return err
+	}
+
+	c.columns = make(map[string]int, len(header))
+	for i, name := range header {
+		c.columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := c.columns[name]; !ok {
+			return fmt.Errorf("missing CSV column %q", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// importUserServer accepts every record except emails starting with "taken"
+type importUserServer struct {
+	pb.UnimplementedUserServiceServer
+	dryRun  bool
+	records []*pb.CreateUserRequest
+}
+
+func (s *importUserServer) ImportUsers(stream grpc.BidiStreamingServer[pb.ImportUsersRequest, pb.ImportUsersResponse]) error {
+	for index := int64(0); ; index++ {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if index == 0 {
+			s.dryRun = req.DryRun
+		}
+		s.records = append(s.records, req.User)
+
+		resp := &pb.ImportUsersResponse{Index: index, User: &pb.User{Email: req.User.Email}}
+		if strings.HasPrefix(req.User.Email, "taken") {
+			resp = &pb.ImportUsersResponse{Index: index, Error: &pb.ImportError{
+				Code:            int32(codes.AlreadyExists),
+				Message:         "already exists",
+				Reason:          "ALREADY_EXISTS",
+				FieldViolations: []*pb.FieldViolation{{Field: "email", Description: "is taken"}},
+			}}
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func TestUserServiceClient_ImportUsersFrom_CSV(t *testing.T) {
+	srv := &importUserServer{}
+	client := newTestClient(t, srv)
+
+	input := strings.Join([]string{
+		"id,email,first_name,last_name,password",
+		"1,john@example.com,John,Doe,secret1",
+		`2,taken@example.com,Jane,"Roe, Jr.",secret2`,
+		"3,bob@example.com,Bob,Smith,secret3",
+	}, "\n")
+
+	result, err := client.ImportUsersFrom(context.Background(), strings.NewReader(input), ExportCSV, true)
+
+	require.NoError(t, err)
+	assert.True(t, srv.dryRun)
+	require.Len(t, srv.records, 3)
+	assert.Equal(t, "Roe, Jr.", srv.records[1].LastName)
+	assert.Equal(t, "secret3", srv.records[2].Password)
+
+	assert.Equal(t, int64(2), result.Succeeded)
+	require.Len(t, result.Failures, 1)
+	failure := result.Failures[0]
+	assert.Equal(t, int64(2), failure.Record)
+	assert.Equal(t, "taken@example.com", failure.Email)
+	assert.ErrorIs(t, failure.Err, ErrAlreadyExists)
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, failure.Err, &validationErr)
+	assert.Equal(t, "email", validationErr.Violations[0].Field)
+}
+
+func TestUserServiceClient_ImportUsersFrom_JSONL(t *testing.T) {
+	srv := &importUserServer{}
+	client := newTestClient(t, srv)
+
+	input := `{"email":"john@example.com","password":"secret","first_name":"John","last_name":"Doe","role":"ROLE_USER"}
+
+{"email":"jane@example.com","password":"secret","first_name":"Jane","last_name":"Roe"}
+`
+	result, err := client.ImportUsersFrom(context.Background(), strings.NewReader(input), ExportJSONL, false)
+
+	require.NoError(t, err)
+	assert.False(t, srv.dryRun)
+	assert.Equal(t, int64(2), result.Succeeded)
+	assert.Empty(t, result.Failures)
+}
+
+func TestUserServiceClient_ImportUsersFrom_MalformedInput(t *testing.T) {
+	client := newTestClient(t, &importUserServer{})
+
+	tests := []struct {
+		name   string
+		input  string
+		format ExportFormat
+	}{
+		{"missing column", "email,first_name,last_name\na@example.com,A,B\n", ExportCSV},
+		{"ragged row", "email,password,first_name,last_name\na@example.com,x,A,B\nb@example.com,x\n", ExportCSV},
+		{"invalid json", "{\"email\":\"a@example.com\"}\nnot json\n", ExportJSONL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ImportUsersFrom(context.Background(), strings.NewReader(tt.input), tt.format, false)
+			assert.Error(t, err)
+			assert.False(t, errors.Is(err, ErrCanceled), "got %v", err)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/token"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+const (
+	// DefaultKeyRefreshInterval is the shortest time between two fetches
+	// of the signing keys
+	DefaultKeyRefreshInterval = time.Minute
+	// DefaultKeyMaxAge is how long fetched signing keys are used before
+	// they are fetched again, so that keys removed by the service are
+	// eventually dropped
+	DefaultKeyMaxAge = time.Hour
+)
+
+// GetSigningKeys returns the public keys access tokens are signed with
+func (c *UserServiceClient) GetSigningKeys(ctx context.Context, req *pb.GetSigningKeysRequest) (*pb.GetSigningKeysResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.GetSigningKeys(ctx, req)
+}
+
+// KeySet caches the service's public signing keys so that access tokens
+// can be verified offline, with token.NewVerifier(keySet, ...). Keys are
+// fetched on first use and again when a token names an unknown key, but at
+// most once per refresh interval so that tokens with made-up key IDs cannot
+// flood the service. It is safe for concurrent use.
+type KeySet struct {
+	client          *UserServiceClient
+	refreshInterval time.Duration
+	maxAge          time.Duration
+	now             func() time.Time
+
+	// refreshMu serializes fetches, so concurrent misses share one
+	refreshMu   sync.Mutex
+	mu          sync.RWMutex
+	keys        map[string]*token.Key
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+// NewKeySet creates a key cache backed by c. A non-positive
+// refreshInterval uses DefaultKeyRefreshInterval.
+func NewKeySet(c *UserServiceClient, refreshInterval time.Duration) *KeySet {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultKeyRefreshInterval
+	}
+
+	return &KeySet{
+		client:          c,
+		refreshInterval: refreshInterval,
+		maxAge:          DefaultKeyMaxAge,
+		now:             time.Now,
+		keys:            make(map[string]*token.Key),
+	}
+}
+
+// VerificationKey implements token.KeySource
+func (k *KeySet) VerificationKey(ctx context.Context, id string) (*token.Key, error) {
+	if key, fresh := k.cached(id); key != nil && fresh {
+		return key, nil
+	}
+
+	k.refreshMu.Lock()
+	defer k.refreshMu.Unlock()
+
+	// Another caller may have fetched the keys while we waited
+	key, fresh := k.cached(id)
+	if key != nil && fresh {
+		return key, nil
+	}
+
+	if k.now().Sub(k.attemptedAt) >= k.refreshInterval {
+		err := k.refresh(ctx)
+		if err != nil && key == nil {
+			return nil, err
+		}
+		// Stale keys are still used while the service is unreachable
+		if err == nil {
+			key, _ = k.cached(id)
+		}
+	}
+
+	if key == nil {
+		return nil, fmt.Errorf("%w: %s", token.ErrUnknownKey, id)
+	}
+	return key, nil
+}
+
+// Refresh fetches the signing keys now, regardless of the refresh interval
+func (k *KeySet) Refresh(ctx context.Context) error {
+	k.refreshMu.Lock()
+	defer k.refreshMu.Unlock()
+
+	return k.refresh(ctx)
+}
+
+// cached returns the cached key with the given ID, if any, and whether the
+// cache is recent enough to be used without fetching
+func (k *KeySet) cached(id string) (*token.Key, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.keys[id], k.now().Sub(k.fetchedAt) < k.maxAge
+}
+
+// refresh replaces the cached keys; k.refreshMu must be held
+func (k *KeySet) refresh(ctx context.Context) error {
+	k.attemptedAt = k.now()
+
+	resp, err := k.client.GetSigningKeys(ctx, &pb.GetSigningKeysRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+
+	keys := make(map[string]*token.Key, len(resp.Keys))
+	for _, jwk := range resp.Keys {
+		// Keys of types this version does not support are skipped, so that
+		// the service can introduce new ones
+		key, err := token.ParseJWK(convertJWKFromProto(jwk))
+		if err != nil {
+			continue
+		}
+		keys[key.ID] = key
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys = keys
+	k.fetchedAt = k.attemptedAt
+	return nil
+}
+
+func convertJWKFromProto(jwk *pb.JsonWebKey) token.JWK {
+	return token.JWK{
+		KeyType:   jwk.Kty,
+		KeyID:     jwk.Kid,
+		Algorithm: jwk.Alg,
+		Use:       jwk.Use,
+		N:         jwk.N,
+		E:         jwk.E,
+		Curve:     jwk.Crv,
+		X:         jwk.X,
+		Y:         jwk.Y,
+	}
+}
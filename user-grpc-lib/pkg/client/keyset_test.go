@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/token"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// signingKeyServer publishes the public keys of keys and counts fetches
+func signingKeyServer(keys *token.KeySet, fetches *atomic.Int32) *fakeUserServer {
+	return &fakeUserServer{
+		getSigningKeys: func(ctx context.Context, req *pb.GetSigningKeysRequest) (*pb.GetSigningKeysResponse, error) {
+			fetches.Add(1)
+
+			resp := &pb.GetSigningKeysResponse{}
+			for _, jwk := range keys.PublicJWKS().Keys {
+				resp.Keys = append(resp.Keys, &pb.JsonWebKey{
+					Kty: jwk.KeyType, Kid: jwk.KeyID, Alg: jwk.Algorithm, Use: jwk.Use,
+					N: jwk.N, E: jwk.E, Crv: jwk.Curve, X: jwk.X, Y: jwk.Y,
+				})
+			}
+			return resp, nil
+		},
+	}
+}
+
+func TestKeySet_VerifiesOffline(t *testing.T) {
+	key, err := token.GenerateKey("k1", token.ES256)
+	require.NoError(t, err)
+	keys, err := token.NewKeySet(key)
+	require.NoError(t, err)
+	issuer := token.NewIssuer(keys)
+
+	var fetches atomic.Int32
+	client := newTestClient(t, signingKeyServer(keys, &fetches))
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	keySet := NewKeySet(client, time.Minute)
+	keySet.now = func() time.Time { return now }
+	verifier := token.NewVerifier(keySet)
+
+	for i := 0; i < 3; i++ {
+		raw, _, err := issuer.Issue(token.Claims{Subject: "123"})
+		require.NoError(t, err)
+		claims, err := verifier.Verify(context.Background(), raw)
+		require.NoError(t, err)
+		assert.Equal(t, "123", claims.Subject)
+	}
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// A token signed by a key rotated in after the last fetch is only
+	// accepted once the refresh interval has passed
+	next, err := token.GenerateKey("k2", token.EdDSA)
+	require.NoError(t, err)
+	require.NoError(t, keys.Rotate(next))
+	raw, _, err := issuer.Issue(token.Claims{Subject: "123"})
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Second)
+	_, err = verifier.Verify(context.Background(), raw)
+	assert.ErrorIs(t, err, token.ErrUnknownKey)
+	assert.Equal(t, int32(1), fetches.Load())
+
+	now = now.Add(time.Minute)
+	_, err = verifier.Verify(context.Background(), raw)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), fetches.Load())
+
+	// Made-up key IDs do not cause more fetches
+	for _, kid := range []string{"x1", "x2", "x3"} {
+		_, err = keySet.VerificationKey(context.Background(), kid)
+		assert.ErrorIs(t, err, token.ErrUnknownKey)
+	}
+	assert.Equal(t, int32(2), fetches.Load())
+}
+
+func TestKeySet_KeepsStaleKeysWhenUnavailable(t *testing.T) {
+	key, err := token.GenerateKey("k1", token.EdDSA)
+	require.NoError(t, err)
+	keys, err := token.NewKeySet(key)
+	require.NoError(t, err)
+
+	var fetches atomic.Int32
+	srv := signingKeyServer(keys, &fetches)
+	publish := srv.getSigningKeys
+	client := newTestClient(t, srv)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	keySet := NewKeySet(client, 0)
+	keySet.now = func() time.Time { return now }
+	require.NoError(t, keySet.Refresh(context.Background()))
+
+	srv.getSigningKeys = func(ctx context.Context, req *pb.GetSigningKeysRequest) (*pb.GetSigningKeysResponse, error) {
+		fetches.Add(1)
+		return nil, status.Error(codes.Internal, "database down")
+	}
+
+	now = now.Add(DefaultKeyMaxAge + time.Minute)
+	cached, err := keySet.VerificationKey(context.Background(), "k1")
+	require.NoError(t, err)
+	assert.Equal(t, "k1", cached.ID)
+	assert.Equal(t, int32(2), fetches.Load())
+
+	_, err = keySet.VerificationKey(context.Background(), "k2")
+	assert.ErrorIs(t, err, token.ErrUnknownKey)
+
+	srv.getSigningKeys = publish
+	require.NoError(t, keySet.Refresh(context.Background()))
+	assert.Equal(t, int32(3), fetches.Load())
+}
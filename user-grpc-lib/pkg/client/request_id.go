@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// requestIDUnaryInterceptor gives requests with an empty request_id field a
+// random one. The channel resends the same message on every retry, so the
+// server recognizes the attempts as a single request.
+func requestIDUnaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if msg, ok := req.(proto.Message); ok {
+		field := msg.ProtoReflect().Descriptor().Fields().ByName("request_id")
+		if field != nil && field.Kind() == protoreflect.StringKind && msg.ProtoReflect().Get(field).String() == "" {
+			requestID, err := NewRequestID()
+			if err != nil {
+				return err
+			}
+
+			// The caller's message is left untouched
+			msg = proto.Clone(msg)
+			msg.ProtoReflect().Set(field, protoreflect.ValueOfString(requestID))
+			req = msg
+		}
+	}
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// NewRequestID returns a random UUID suitable for the request_id field of
+// write requests. Reuse it when retrying a call manually.
+func NewRequestID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate request id: %w", err)
+	}
+
+	// Version 4, RFC 4122 variant
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// idempotentMethods are the reads, which are safe to retry and are retried
+// whenever MaxRetries > 0. RefreshToken is never retried: a retry after a
+// lost response would reuse the replaced refresh token and end the session.
+// Nor are RevokeToken, RevokeAllForUser and RevokeSession, which carry no
+// request_id; each retry of RevokeAllForUser would move its cut-off and
+// revoke tokens issued between the attempts.
+var idempotentMethods = []string{
+	"GetUserByEmail",
+	"GetUserByID",
+	"GetUsers",
+	"SearchUsers",
+	"BatchGetUsers",
+	"GetSigningKeys",
+	"IntrospectToken",
+	"ListSessions",
+}
+
+// writeMethods are only retried when RetryPolicy.RetryWrites is set
+var writeMethods = []string{
+	"CreateUser",
+	"UpdateUser",
+	"DeleteUser",
+	"RestoreUser",
+	"PurgeUser",
+	"Login",
+	"UpdateUserRole",
+	"UpdatePassword",
+}
+
+// RetryPolicy configures how failed calls are retried by the gRPC channel.
+// The number of attempts is taken from Config.MaxRetries.
+type RetryPolicy struct {
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+	// RetryableCodes lists the status codes retried for every method
+	RetryableCodes []codes.Code
+	// MethodRetryableCodes overrides RetryableCodes for individual methods,
+	// keyed by method name (e.g. "GetUserByID")
+	MethodRetryableCodes map[string][]codes.Code
+	// RetryWrites enables retries for non-idempotent methods such as
+	// CreateUser. Write requests without a request_id are given a random one,
+	// so that the server applies a retried write only once.
+	RetryWrites bool
+}
+
+// DefaultRetryPolicy returns a retry policy that retries idempotent methods on Unavailable
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		InitialBackoff:    100 * time.Millisecond,
+		MaxBackoff:        2 * time.Second,
+		BackoffMultiplier: 2,
+		RetryableCodes:    []codes.Code{codes.Unavailable},
+	}
+}
+
+// retryMethodConfigs renders the per-method service config entries that
+// implement policy with maxRetries retries per call. It returns nil when
+// retries are disabled.
+func retryMethodConfigs(maxRetries int, policy *RetryPolicy) ([]jsonMethodConfig, error) {
+	if maxRetries <= 0 {
+		return nil, nil
+	}
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+	if policy.InitialBackoff <= 0 || policy.MaxBackoff <= 0 || policy.BackoffMultiplier <= 0 {
+		return nil, fmt.Errorf("retry policy backoff values must be positive")
+	}
+
+	methods := idempotentMethods
+	if policy.RetryWrites {
+		methods = append(append([]string{}, idempotentMethods...), writeMethods...)
+	}
+
+	var configs []jsonMethodConfig
+	for _, method := range methods {
+		retryableCodes := policy.RetryableCodes
+		if override, ok := policy.MethodRetryableCodes[method]; ok {
+			retryableCodes = override
+		}
+		if len(retryableCodes) == 0 {
+			continue
+		}
+
+		configs = append(configs, jsonMethodConfig{
+			Name: []jsonMethodName{{
+				Service: pb.UserService_ServiceDesc.ServiceName,
+				Method:  method,
+			}},
+			RetryPolicy: &jsonRetryPolicy{
+				MaxAttempts:          maxRetries + 1,
+				InitialBackoff:       formatDuration(policy.InitialBackoff),
+				MaxBackoff:           formatDuration(policy.MaxBackoff),
+				BackoffMultiplier:    policy.BackoffMultiplier,
+				RetryableStatusCodes: retryableCodes,
+			},
+		})
+	}
+
+	return configs, nil
+}
+
+// formatDuration formats d in the protobuf JSON duration format ("1.5s")
+func formatDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
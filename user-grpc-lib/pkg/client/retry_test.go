@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// flakyUserServer fails the first failures calls of every method with code
+type flakyUserServer struct {
+	pb.UnimplementedUserServiceServer
+	failures int32
+	code     codes.Code
+	calls    atomic.Int32
+}
+
+func (s *flakyUserServer) fail() error {
+	if s.calls.Add(1) <= s.failures {
+		return status.Error(s.code, "transient failure")
+	}
+	return nil
+}
+
+func (s *flakyUserServer) GetUserByID(ctx context.Context, req *pb.GetUserByIDRequest) (*pb.GetUserByIDResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return &pb.GetUserByIDResponse{User: &pb.User{Id: req.Id}}, nil
+}
+
+func (s *flakyUserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return &pb.CreateUserResponse{User: &pb.User{Email: req.Email}}, nil
+}
+
+func fastRetries(maxRetries int, retryWrites bool) func(*Config) {
+	return func(config *Config) {
+		config.MaxRetries = maxRetries
+		config.Retry = &RetryPolicy{
+			InitialBackoff:    time.Millisecond,
+			MaxBackoff:        5 * time.Millisecond,
+			BackoffMultiplier: 2,
+			RetryableCodes:    []codes.Code{codes.Unavailable},
+			RetryWrites:       retryWrites,
+		}
+	}
+}
+
+func TestUserServiceClient_RetriesIdempotentMethods(t *testing.T) {
+	srv := &flakyUserServer{failures: 2, code: codes.Unavailable}
+	client := newTestClient(t, srv, fastRetries(3, false))
+
+	resp, err := client.GetUserByID(context.Background(), &pb.GetUserByIDRequest{Id: "123"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "123", resp.User.Id)
+	assert.Equal(t, int32(3), srv.calls.Load())
+}
+
+func TestUserServiceClient_GivesUpAfterMaxRetries(t *testing.T) {
+	srv := &flakyUserServer{failures: 10, code: codes.Unavailable}
+	client := newTestClient(t, srv, fastRetries(2, false))
+
+	_, err := client.GetUserByID(context.Background(), &pb.GetUserByIDRequest{Id: "123"})
+
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, int32(3), srv.calls.Load())
+}
+
+func TestUserServiceClient_DoesNotRetryNonRetryableCodes(t *testing.T) {
+	srv := &flakyUserServer{failures: 1, code: codes.Internal}
+	client := newTestClient(t, srv, fastRetries(3, false))
+
+	_, err := client.GetUserByID(context.Background(), &pb.GetUserByIDRequest{Id: "123"})
+
+	assert.ErrorIs(t, err, ErrInternal)
+	assert.Equal(t, int32(1), srv.calls.Load())
+}
+
+func TestUserServiceClient_WritesRetriedOnlyWhenEnabled(t *testing.T) {
+	tests := []struct {
+		name          string
+		retryWrites   bool
+		expectedError error
+		expectedCalls int32
+	}{
+		{name: "writes not retried by default", expectedError: ErrUnavailable, expectedCalls: 1},
+		{name: "writes retried when enabled", retryWrites: true, expectedCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &flakyUserServer{failures: 1, code: codes.Unavailable}
+			client := newTestClient(t, srv, fastRetries(3, tt.retryWrites))
+
+			_, err := client.CreateUser(context.Background(), &pb.CreateUserRequest{Email: "test@example.com"})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedCalls, srv.calls.Load())
+		})
+	}
+}
+
+func TestRetryServiceConfig(t *testing.T) {
+	js, err := buildServiceConfig(&Config{MaxRetries: 0, Retry: DefaultRetryPolicy()})
+	assert.NoError(t, err)
+	assert.Empty(t, js, "retries disabled when MaxRetries is zero")
+
+	policy := DefaultRetryPolicy()
+	policy.MethodRetryableCodes = map[string][]codes.Code{"GetUsers": nil}
+	js, err = buildServiceConfig(&Config{MaxRetries: 3, Retry: policy})
+	assert.NoError(t, err)
+	assert.Contains(t, js, `"method":"GetUserByID"`)
+	assert.NotContains(t, js, `"method":"GetUsers"`)
+	assert.NotContains(t, js, `"method":"CreateUser"`)
+	assert.Contains(t, js, `"maxAttempts":4`)
+	for _, method := range []string{"RefreshToken", "RevokeToken", "RevokeAllForUser", "RevokeSession"} {
+		assert.NotContains(t, js, `"method":"`+method+`"`)
+	}
+	assert.Contains(t, js, `"initialBackoff":"0.1s"`)
+
+	policy.RetryWrites = true
+	js, err = buildServiceConfig(&Config{MaxRetries: 3, Retry: policy})
+	assert.NoError(t, err)
+	assert.Contains(t, js, `"method":"CreateUser"`)
+	assert.NotContains(t, js, `"method":"RevokeAllForUser"`)
+
+	_, err = buildServiceConfig(&Config{MaxRetries: 3, Retry: &RetryPolicy{}})
+	assert.Error(t, err)
+}
+
+func TestUserServiceClient_RetriedWritesKeepRequestID(t *testing.T) {
+	var requestIDs []string
+	client := newTestClient(t, &fakeUserServer{
+		createUser: func(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+			requestIDs = append(requestIDs, req.RequestId)
+			if len(requestIDs) == 1 {
+				return nil, status.Error(codes.Unavailable, "transient failure")
+			}
+			return &pb.CreateUserResponse{User: &pb.User{Email: req.Email}}, nil
+		},
+	}, fastRetries(3, true))
+
+	req := &pb.CreateUserRequest{Email: "test@example.com"}
+	_, err := client.CreateUser(context.Background(), req)
+
+	require.NoError(t, err)
+	require.Len(t, requestIDs, 2)
+	assert.NotEmpty(t, requestIDs[0])
+	assert.Equal(t, requestIDs[0], requestIDs[1])
+	assert.Empty(t, req.RequestId, "the caller's request must not be modified")
+
+	// An explicit request ID is sent as is
+	requestIDs = nil
+	_, err = client.CreateUser(context.Background(), &pb.CreateUserRequest{Email: "test@example.com", RequestId: "mine"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mine", "mine"}, requestIDs)
+}
+
+func TestNewRequestID(t *testing.T) {
+	id, err := NewRequestID()
+	require.NoError(t, err)
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+
+	other, err := NewRequestID()
+	require.NoError(t, err)
+	assert.NotEqual(t, id, other)
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Load-balancing policies supported by Config.LoadBalancingPolicy
+const (
+	PickFirst  = "pick_first"
+	RoundRobin = "round_robin"
+)
+
+type jsonServiceConfig struct {
+	LoadBalancingConfig []map[string]struct{} `json:"loadBalancingConfig,omitempty"`
+	MethodConfig        []jsonMethodConfig    `json:"methodConfig,omitempty"`
+}
+
+type jsonMethodConfig struct {
+	Name        []jsonMethodName `json:"name"`
+	RetryPolicy *jsonRetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+type jsonMethodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method,omitempty"`
+}
+
+type jsonRetryPolicy struct {
+	MaxAttempts          int          `json:"maxAttempts"`
+	InitialBackoff       string       `json:"initialBackoff"`
+	MaxBackoff           string       `json:"maxBackoff"`
+	BackoffMultiplier    float64      `json:"backoffMultiplier"`
+	RetryableStatusCodes []codes.Code `json:"retryableStatusCodes"`
+}
+
+// buildServiceConfig renders the default gRPC service config for config.
+// It returns an empty string when the channel defaults are sufficient.
+func buildServiceConfig(config *Config) (string, error) {
+	serviceConfig := jsonServiceConfig{}
+
+	switch config.LoadBalancingPolicy {
+	case "":
+	case PickFirst, RoundRobin:
+		serviceConfig.LoadBalancingConfig = []map[string]struct{}{
+			{config.LoadBalancingPolicy: {}},
+		}
+	default:
+		return "", fmt.Errorf("unsupported load balancing policy %q", config.LoadBalancingPolicy)
+	}
+
+	methodConfigs, err := retryMethodConfigs(config.MaxRetries, config.Retry)
+	if err != nil {
+		return "", err
+	}
+	serviceConfig.MethodConfig = methodConfigs
+
+	if serviceConfig.LoadBalancingConfig == nil && serviceConfig.MethodConfig == nil {
+		return "", nil
+	}
+
+	js, err := json.Marshal(serviceConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode service config: %w", err)
+	}
+	return string(js), nil
+}
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
 
 	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
@@ -16,12 +18,28 @@ import (
 
 // Config ClientConfig holds configuration for the gRPC client
 type Config struct {
+	// Address is a gRPC target such as "localhost:50051", "dns:///users.internal:50051",
+	// "unix:///var/run/users.sock" or "passthrough:///10.0.0.1:50051"
 	Address    string
 	TLS        bool
 	TLSConfig  *tls.Config
 	Timeout    time.Duration
 	MaxRetries int
-	KeepAlive  *keepalive.ClientParameters
+	// Retry controls backoff and which methods are retried; nil uses
+	// DefaultRetryPolicy. Retries are disabled when MaxRetries is zero.
+	Retry     *RetryPolicy
+	KeepAlive *keepalive.ClientParameters
+	// LoadBalancingPolicy is PickFirst (the default) or RoundRobin
+	LoadBalancingPolicy string
+	// WaitForReady makes calls wait for the channel to become ready instead
+	// of failing fast while the server is unreachable
+	WaitForReady bool
+	// BlockUntilReady makes NewUserServiceClient wait up to Timeout for the
+	// channel to become READY before returning. It requires a positive
+	// Timeout.
+	BlockUntilReady bool
+	// Credentials authenticate every call, e.g. StaticToken(accessToken)
+	Credentials credentials.PerRPCCredentials
 }
 
 // DefaultClientConfig returns a default client configuration
@@ -31,17 +49,22 @@ func DefaultClientConfig(address string) *Config {
 		TLS:        false,
 		Timeout:    30 * time.Second,
 		MaxRetries: 3,
+		Retry:      DefaultRetryPolicy(),
 		KeepAlive: &keepalive.ClientParameters{
 			Time:                10 * time.Second,
 			Timeout:             5 * time.Second,
 			PermitWithoutStream: true,
 		},
+		LoadBalancingPolicy: PickFirst,
 	}
 }
 
-// UserServiceClient wraps the generated gRPC client with additional functionality
+// UserServiceClient wraps the generated gRPC client with additional functionality.
+// Failed calls return an *Error that matches the sentinel errors in this
+// package (ErrNotFound, ErrAlreadyExists, ...) with errors.Is.
 type UserServiceClient struct {
 	client pb.UserServiceClient
+	health healthpb.HealthClient
 	conn   *grpc.ClientConn
 	config *Config
 }
@@ -51,9 +74,36 @@ func NewUserServiceClient(config *Config) (*UserServiceClient, error) {
 	if config == nil {
 		return nil, fmt.Errorf("client config is required")
 	}
+	if config.BlockUntilReady && config.Timeout <= 0 {
+		return nil, fmt.Errorf("BlockUntilReady requires a positive Timeout")
+	}
 
 	opts := []grpc.DialOption{
-		grpc.WithKeepaliveParams(*config.KeepAlive),
+		grpc.WithChainUnaryInterceptor(errorUnaryInterceptor),
+		grpc.WithChainStreamInterceptor(errorStreamInterceptor),
+	}
+	if config.KeepAlive != nil {
+		opts = append(opts, grpc.WithKeepaliveParams(*config.KeepAlive))
+	}
+	if config.WaitForReady {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.WaitForReady(true)))
+	}
+	if config.Credentials != nil {
+		opts = append(opts, grpc.WithPerRPCCredentials(config.Credentials))
+	}
+
+	serviceConfig, err := buildServiceConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if serviceConfig != "" {
+		opts = append(opts, grpc.WithDefaultServiceConfig(serviceConfig))
+	}
+	if config.MaxRetries > 0 {
+		opts = append(opts, grpc.WithMaxCallAttempts(config.MaxRetries+1))
+		if config.Retry != nil && config.Retry.RetryWrites {
+			opts = append(opts, grpc.WithChainUnaryInterceptor(requestIDUnaryInterceptor))
+		}
 	}
 
 	// Configure TLS or insecure connection
@@ -64,20 +114,45 @@ func NewUserServiceClient(config *Config) (*UserServiceClient, error) {
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
-	conn, err := grpc.Dial(config.Address, opts...)
+	conn, err := grpc.NewClient(config.Address, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to gRPC server: %w", err)
+		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+
+	if config.BlockUntilReady {
+		if err := waitForReady(conn, config.Timeout); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
 	}
 
 	client := pb.NewUserServiceClient(conn)
 
 	return &UserServiceClient{
 		client: client,
+		health: healthpb.NewHealthClient(conn),
 		conn:   conn,
 		config: config,
 	}, nil
 }
 
+// waitForReady connects conn and waits up to timeout for it to become READY
+func waitForReady(conn *grpc.ClientConn, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("gRPC channel to %s not ready within %s (last state %s)", conn.Target(), timeout, state)
+		}
+	}
+}
+
 // Close closes the client connection
 func (c *UserServiceClient) Close() error {
 	if c.conn != nil {
@@ -134,6 +209,118 @@ func (c *UserServiceClient) DeleteUser(ctx context.Context, req *pb.DeleteUserRe
 	return c.client.DeleteUser(ctx, req)
 }
 
+// RestoreUser undoes the soft deletion of a user. The actor must be at
+// least a moderator.
+func (c *UserServiceClient) RestoreUser(ctx context.Context, req *pb.RestoreUserRequest) (*pb.RestoreUserResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.RestoreUser(ctx, req)
+}
+
+// PurgeUser permanently removes a soft-deleted user. The actor must be an
+// admin.
+func (c *UserServiceClient) PurgeUser(ctx context.Context, req *pb.PurgeUserRequest) (*pb.PurgeUserResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.PurgeUser(ctx, req)
+}
+
+// UpdateUserRole changes a user's role on behalf of the given actor role
+func (c *UserServiceClient) UpdateUserRole(ctx context.Context, req *pb.UpdateUserRoleRequest) (*pb.UpdateUserRoleResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.UpdateUserRole(ctx, req)
+}
+
+// UpdatePassword changes a user's password after verifying the current one
+func (c *UserServiceClient) UpdatePassword(ctx context.Context, req *pb.UpdatePasswordRequest) (*pb.UpdatePasswordResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.UpdatePassword(ctx, req)
+}
+
+// SearchUsers finds users whose email or name match a free-text query
+func (c *UserServiceClient) SearchUsers(ctx context.Context, req *pb.SearchUsersRequest) (*pb.SearchUsersResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.SearchUsers(ctx, req)
+}
+
+// BatchGetUsers retrieves many users by ID in a single call
+func (c *UserServiceClient) BatchGetUsers(ctx context.Context, req *pb.BatchGetUsersRequest) (*pb.BatchGetUsersResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.BatchGetUsers(ctx, req)
+}
+
+// Login authenticates a user and returns an access token
+func (c *UserServiceClient) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.Login(ctx, req)
+}
+
+// IntrospectToken reports whether an access token is active and describes it
+func (c *UserServiceClient) IntrospectToken(ctx context.Context, req *pb.IntrospectTokenRequest) (*pb.IntrospectTokenResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.IntrospectToken(ctx, req)
+}
+
+// RevokeToken revokes an access token before it expires. It is never
+// retried.
+func (c *UserServiceClient) RevokeToken(ctx context.Context, req *pb.RevokeTokenRequest) (*pb.RevokeTokenResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.RevokeToken(ctx, req)
+}
+
+// RevokeAllForUser revokes every access token issued to a user so far and
+// ends their sessions. It is never retried, since a retry would also revoke
+// tokens issued after the first attempt.
+func (c *UserServiceClient) RevokeAllForUser(ctx context.Context, req *pb.RevokeAllForUserRequest) (*pb.RevokeAllForUserResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.RevokeAllForUser(ctx, req)
+}
+
+// RefreshToken exchanges a refresh token for a new access token and a new
+// refresh token. The old refresh token must not be used again: doing so
+// ends the session, so this call is never retried.
+func (c *UserServiceClient) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.RefreshToken(ctx, req)
+}
+
+// ListSessions lists the sessions of a user, most recently seen first
+func (c *UserServiceClient) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.ListSessions(ctx, req)
+}
+
+// RevokeSession ends a session, so that its refresh token stops working.
+// It is never retried.
+func (c *UserServiceClient) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.client.RevokeSession(ctx, req)
+}
+
 // withTimeout adds a timeout to the context if one isn't already set
 func (c *UserServiceClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
 	if _, hasDeadline := ctx.Deadline(); hasDeadline {
@@ -142,14 +329,42 @@ func (c *UserServiceClient) withTimeout(ctx context.Context) (context.Context, c
 	return context.WithTimeout(ctx, c.config.Timeout)
 }
 
-// HealthCheck performs a simple health check by calling GetUsers with minimal parameters
+// HealthCheck queries the standard gRPC health service for UserService and
+// returns an error matching ErrUnavailable unless the service is SERVING
 func (c *UserServiceClient) HealthCheck(ctx context.Context) error {
 	ctx, cancel := c.withTimeout(ctx)
 	defer cancel()
 
-	_, err := c.client.GetUsers(ctx, &pb.GetUsersRequest{
-		Page:     1,
-		PageSize: 1,
+	resp, err := c.health.Check(ctx, &healthpb.HealthCheckRequest{
+		Service: pb.UserService_ServiceDesc.ServiceName,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("%w: health status %s", ErrUnavailable, resp.Status)
+	}
+	return nil
+}
+
+// WatchHealth streams serving status changes for UserService to onChange
+// until ctx is canceled or the stream fails
+func (c *UserServiceClient) WatchHealth(ctx context.Context, onChange func(healthpb.HealthCheckResponse_ServingStatus)) error {
+	stream, err := c.health.Watch(ctx, &healthpb.HealthCheckRequest{
+		Service: pb.UserService_ServiceDesc.ServiceName,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		onChange(resp.Status)
+	}
 }
@@ -0,0 +1,315 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// fakeUserServer lets each test decide how the server responds
+type fakeUserServer struct {
+	pb.UnimplementedUserServiceServer
+	getUserByID func(context.Context, *pb.GetUserByIDRequest) (*pb.GetUserByIDResponse, error)
+	createUser  func(context.Context, *pb.CreateUserRequest) (*pb.CreateUserResponse, error)
+	updateUser  func(context.Context, *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error)
+
+	getSigningKeys func(context.Context, *pb.GetSigningKeysRequest) (*pb.GetSigningKeysResponse, error)
+}
+
+func (s *fakeUserServer) GetUserByID(ctx context.Context, req *pb.GetUserByIDRequest) (*pb.GetUserByIDResponse, error) {
+	return s.getUserByID(ctx, req)
+}
+
+func (s *fakeUserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	return s.createUser(ctx, req)
+}
+
+func (s *fakeUserServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	return s.updateUser(ctx, req)
+}
+
+func (s *fakeUserServer) GetSigningKeys(ctx context.Context, req *pb.GetSigningKeysRequest) (*pb.GetSigningKeysResponse, error) {
+	return s.getSigningKeys(ctx, req)
+}
+
+// newTestClient starts srv on a local port and returns a client connected to it
+func newTestClient(t *testing.T, srv pb.UserServiceServer, configure ...func(*Config)) *UserServiceClient {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	serveOn(t, lis, srv)
+
+	config := DefaultClientConfig(lis.Addr().String())
+	for _, fn := range configure {
+		fn(config)
+	}
+
+	client, err := NewUserServiceClient(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+func TestUserServiceClient_TypedErrors(t *testing.T) {
+	client := newTestClient(t, &fakeUserServer{
+		getUserByID: func(ctx context.Context, req *pb.GetUserByIDRequest) (*pb.GetUserByIDResponse, error) {
+			st, _ := status.New(codes.NotFound, "user not found").WithDetails(&errdetails.ErrorInfo{
+				Reason: "NOT_FOUND",
+				Domain: "user.v1",
+			})
+			return nil, st.Err()
+		},
+	})
+
+	_, err := client.GetUserByID(context.Background(), &pb.GetUserByIDRequest{Id: "123"})
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrAlreadyExists)
+
+	var clientErr *Error
+	require.ErrorAs(t, err, &clientErr)
+	assert.Equal(t, "NOT_FOUND", clientErr.Reason)
+	assert.Equal(t, "user.v1", clientErr.Domain)
+
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "user not found", st.Message())
+}
+
+func TestConvertError_Sentinels(t *testing.T) {
+	tests := []struct {
+		code     codes.Code
+		sentinel error
+	}{
+		{codes.Unimplemented, ErrUnimplemented},
+		{codes.Unknown, ErrUnknown},
+		{codes.DataLoss, ErrDataLoss},
+		{codes.Internal, ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			err := convertError(status.Error(tt.code, "failed"))
+			assert.ErrorIs(t, err, tt.sentinel)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestUserServiceClient_ETagMismatch(t *testing.T) {
+	client := newTestClient(t, &fakeUserServer{
+		updateUser: func(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+			st, _ := status.New(codes.Aborted, "etag mismatch").WithDetails(&errdetails.ErrorInfo{
+				Reason:   "ETAG_MISMATCH",
+				Domain:   "user.v1",
+				Metadata: map[string]string{models.MetadataCurrentETag: "v2"},
+			})
+			return nil, st.Err()
+		},
+	})
+
+	_, err := client.UpdateUser(context.Background(), &pb.UpdateUserRequest{Id: "123", ExpectedEtag: "v1"})
+
+	assert.ErrorIs(t, err, ErrAborted)
+	assert.Equal(t, "v2", CurrentETag(err))
+	assert.Empty(t, CurrentETag(ErrNotFound))
+}
+
+func TestUserServiceClient_ValidationError(t *testing.T) {
+	client := newTestClient(t, &fakeUserServer{
+		createUser: func(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+			st, _ := status.New(codes.InvalidArgument, "validation failed").WithDetails(&errdetails.BadRequest{
+				FieldViolations: []*errdetails.BadRequest_FieldViolation{
+					{Field: "email", Description: "must be a valid address"},
+				},
+			})
+			return nil, st.Err()
+		},
+	})
+
+	_, err := client.CreateUser(context.Background(), &pb.CreateUserRequest{Email: "bad"})
+
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []FieldViolation{{Field: "email", Description: "must be a valid address"}}, validationErr.Violations)
+}
+
+func TestConvertError_NonStatusError(t *testing.T) {
+	plain := errors.New("boom")
+	assert.Same(t, plain, convertError(plain))
+	assert.NoError(t, convertError(nil))
+}
+
+// serveOn serves srv on lis until the test finishes
+func serveOn(t *testing.T, lis net.Listener, srv pb.UserServiceServer) {
+	t.Helper()
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterUserServiceServer(grpcServer, srv)
+	go func() { _ = grpcServer.Serve(lis) }()
+	t.Cleanup(grpcServer.Stop)
+}
+
+func TestNewUserServiceClient_Targets(t *testing.T) {
+	srv := &fakeUserServer{
+		getUserByID: func(ctx context.Context, req *pb.GetUserByIDRequest) (*pb.GetUserByIDResponse, error) {
+			return &pb.GetUserByIDResponse{User: &pb.User{Id: req.Id}}, nil
+		},
+	}
+
+	tcpLis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	serveOn(t, tcpLis, srv)
+
+	unixLis, err := net.Listen("unix", filepath.Join(t.TempDir(), "users.sock"))
+	require.NoError(t, err)
+	serveOn(t, unixLis, srv)
+
+	tests := []struct {
+		name   string
+		target string
+		policy string
+	}{
+		{name: "plain address", target: tcpLis.Addr().String()},
+		{name: "dns scheme", target: "dns:///" + tcpLis.Addr().String()},
+		{name: "passthrough scheme", target: "passthrough:///" + tcpLis.Addr().String()},
+		{name: "unix scheme", target: "unix://" + unixLis.Addr().String()},
+		{name: "round robin", target: "dns:///" + tcpLis.Addr().String(), policy: RoundRobin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultClientConfig(tt.target)
+			config.Timeout = 5 * time.Second
+			config.BlockUntilReady = true
+			if tt.policy != "" {
+				config.LoadBalancingPolicy = tt.policy
+			}
+
+			client, err := NewUserServiceClient(config)
+			require.NoError(t, err)
+			defer client.Close()
+
+			resp, err := client.GetUserByID(context.Background(), &pb.GetUserByIDRequest{Id: "123"})
+			assert.NoError(t, err)
+			assert.Equal(t, "123", resp.User.Id)
+		})
+	}
+}
+
+func TestNewUserServiceClient_BlockUntilReadyTimesOut(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+
+	config := DefaultClientConfig(addr)
+	config.Timeout = 200 * time.Millisecond
+	config.BlockUntilReady = true
+
+	_, err = NewUserServiceClient(config)
+	assert.Error(t, err)
+}
+
+func TestNewUserServiceClient_BlockUntilReadyRequiresTimeout(t *testing.T) {
+	config := DefaultClientConfig("localhost:50051")
+	config.Timeout = 0
+	config.BlockUntilReady = true
+
+	_, err := NewUserServiceClient(config)
+	assert.ErrorContains(t, err, "Timeout")
+}
+
+func TestNewUserServiceClient_InvalidLoadBalancingPolicy(t *testing.T) {
+	config := DefaultClientConfig("localhost:50051")
+	config.LoadBalancingPolicy = "weighted_random"
+
+	_, err := NewUserServiceClient(config)
+	assert.Error(t, err)
+}
+
+func TestUserServiceClient_HealthCheck(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	healthServer := health.NewServer()
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	go func() { _ = grpcServer.Serve(lis) }()
+	t.Cleanup(grpcServer.Stop)
+
+	client, err := NewUserServiceClient(DefaultClientConfig(lis.Addr().String()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	service := pb.UserService_ServiceDesc.ServiceName
+
+	healthServer.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	assert.NoError(t, client.HealthCheck(context.Background()))
+
+	healthServer.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	assert.ErrorIs(t, client.HealthCheck(context.Background()), ErrUnavailable)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	statuses := make(chan healthpb.HealthCheckResponse_ServingStatus, 2)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.WatchHealth(ctx, func(s healthpb.HealthCheckResponse_ServingStatus) {
+			statuses <- s
+		})
+	}()
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, <-statuses)
+	healthServer.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, <-statuses)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestUserServiceClient_Credentials(t *testing.T) {
+	var authorization []string
+	client := newTestClient(t, &fakeUserServer{
+		getUserByID: func(ctx context.Context, req *pb.GetUserByIDRequest) (*pb.GetUserByIDResponse, error) {
+			authorization = metadata.ValueFromIncomingContext(ctx, "authorization")
+			return &pb.GetUserByIDResponse{User: &pb.User{Id: req.Id}}, nil
+		},
+	}, func(config *Config) {
+		credentials := StaticToken("secret")
+		credentials.AllowInsecure = true
+		config.Credentials = credentials
+	})
+
+	_, err := client.GetUserByID(context.Background(), &pb.GetUserByIDRequest{Id: "123"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bearer secret"}, authorization)
+}
+
+func TestNewUserServiceClient_CredentialsRequireTLS(t *testing.T) {
+	config := DefaultClientConfig("localhost:50051")
+	config.Credentials = StaticToken("secret")
+
+	_, err := NewUserServiceClient(config)
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// WatchUsers opens the change feed. Like ExportUsers it does not apply
+// Config.Timeout; the stream runs until ctx is done or the server ends it.
+func (c *UserServiceClient) WatchUsers(ctx context.Context, req *pb.WatchUsersRequest) (pb.UserService_WatchUsersClient, error) {
+	return c.client.WatchUsers(ctx, req)
+}
+
+// WatchUsersFunc calls fn for every event until ctx is done or fn returns
+// an error. When the stream breaks with ErrUnavailable, or the server drops
+// the watcher with ErrResourceExhausted for falling behind, it is reopened
+// after the last received revision, up to Config.MaxRetries times in a row.
+// ErrOutOfRange means events were missed and the caller must resynchronize
+// before watching again from revision 0.
+func (c *UserServiceClient) WatchUsersFunc(ctx context.Context, req *pb.WatchUsersRequest, fn func(*pb.UserEvent) error) error {
+	req = proto.Clone(req).(*pb.WatchUsersRequest)
+	failures := 0
+
+	// Errors from fn are returned as-is and never retried
+	var fnErr error
+	handle := func(event *pb.UserEvent) error {
+		fnErr = fn(event)
+		return fnErr
+	}
+
+	for {
+		received, err := c.watchOnce(ctx, req, handle)
+		if received {
+			failures = 0
+		}
+		if err == nil || fnErr != nil {
+			return err
+		}
+		if !errors.Is(err, ErrUnavailable) && !errors.Is(err, ErrResourceExhausted) || failures >= c.config.MaxRetries {
+			return err
+		}
+
+		if err := sleepContext(ctx, c.resumeBackoff(failures)); err != nil {
+			return err
+		}
+		failures++
+	}
+}
+
+// watchOnce consumes a single watch stream, advancing req.AfterRevision past
+// every delivered event. It reports whether any event was received.
+func (c *UserServiceClient) watchOnce(ctx context.Context, req *pb.WatchUsersRequest, fn func(*pb.UserEvent) error) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.WatchUsers(ctx, req)
+	if err != nil {
+		return false, err
+	}
+
+	received := false
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return received, nil
+		}
+		if err != nil {
+			return received, err
+		}
+
+		received = true
+		if err := fn(resp.Event); err != nil {
+			return received, err
+		}
+		req.AfterRevision = resp.Event.GetRevision()
+	}
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// watchUserServer delivers revisions after the requested one, ending each
+// stream with failure after sending perStream events
+type watchUserServer struct {
+	pb.UnimplementedUserServiceServer
+	perStream int
+	failure   error
+
+	mu             sync.Mutex
+	afterRevisions []int64
+}
+
+func (s *watchUserServer) WatchUsers(req *pb.WatchUsersRequest, stream grpc.ServerStreamingServer[pb.WatchUsersResponse]) error {
+	s.mu.Lock()
+	s.afterRevisions = append(s.afterRevisions, req.AfterRevision)
+	s.mu.Unlock()
+
+	for i := int64(1); i <= int64(s.perStream); i++ {
+		event := &pb.UserEvent{Type: pb.UserEventType_USER_EVENT_TYPE_UPDATED, Revision: req.AfterRevision + i}
+		if err := stream.Send(&pb.WatchUsersResponse{Event: event}); err != nil {
+			return err
+		}
+	}
+	return s.failure
+}
+
+func TestUserServiceClient_WatchUsersFunc_Resumes(t *testing.T) {
+	for _, failure := range []error{
+		status.Error(codes.Unavailable, "connection lost"),
+		status.Error(codes.ResourceExhausted, "slow consumer"),
+	} {
+		t.Run(status.Code(failure).String(), func(t *testing.T) {
+			srv := &watchUserServer{perStream: 2, failure: failure}
+			client := newTestClient(t, srv, fastRetries(1, false))
+
+			stop := errors.New("stop")
+			var revisions []int64
+			err := client.WatchUsersFunc(context.Background(), &pb.WatchUsersRequest{AfterRevision: 10}, func(event *pb.UserEvent) error {
+				revisions = append(revisions, event.Revision)
+				if len(revisions) == 5 {
+					return stop
+				}
+				return nil
+			})
+
+			assert.ErrorIs(t, err, stop)
+			assert.Equal(t, []int64{11, 12, 13, 14, 15}, revisions)
+			assert.Equal(t, []int64{10, 12, 14}, srv.afterRevisions)
+		})
+	}
+}
+
+func TestUserServiceClient_WatchUsersFunc_GivesUp(t *testing.T) {
+	srv := &watchUserServer{failure: status.Error(codes.Unavailable, "connection lost")}
+	client := newTestClient(t, srv, fastRetries(2, false))
+
+	err := client.WatchUsersFunc(context.Background(), &pb.WatchUsersRequest{}, func(*pb.UserEvent) error {
+		return nil
+	})
+
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Len(t, srv.afterRevisions, 3)
+}
+
+func TestUserServiceClient_WatchUsersFunc_OutOfRange(t *testing.T) {
+	srv := &watchUserServer{failure: status.Error(codes.OutOfRange, "revision compacted")}
+	client := newTestClient(t, srv, fastRetries(2, false))
+
+	err := client.WatchUsersFunc(context.Background(), &pb.WatchUsersRequest{AfterRevision: 3}, func(*pb.UserEvent) error {
+		return nil
+	})
+
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	assert.Len(t, srv.afterRevisions, 1)
+}
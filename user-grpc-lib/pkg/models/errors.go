@@ -0,0 +1,132 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorDomain identifies errors produced by this service in google.rpc.ErrorInfo details
+const ErrorDomain = "user.v1"
+
+// MetadataCurrentETag is the google.rpc.ErrorInfo metadata key holding the
+// user's current etag when a conditional write fails
+const MetadataCurrentETag = "current_etag"
+
+// Machine-readable reasons reported in google.rpc.ErrorInfo details
+const (
+	ReasonNotFound           = "NOT_FOUND"
+	ReasonAlreadyExists      = "ALREADY_EXISTS"
+	ReasonInvalidCredentials = "INVALID_CREDENTIALS"
+	ReasonInvalidToken       = "INVALID_TOKEN"
+	ReasonTokenReused        = "TOKEN_REUSED"
+	ReasonInsufficientRights = "INSUFFICIENT_RIGHTS"
+	ReasonValidationFailed   = "VALIDATION_FAILED"
+	ReasonNotDeleted         = "NOT_DELETED"
+	ReasonETagMismatch       = "ETAG_MISMATCH"
+	ReasonRequestInProgress  = "REQUEST_IN_PROGRESS"
+	ReasonRevisionCompacted  = "REVISION_COMPACTED"
+	ReasonSlowConsumer       = "SLOW_CONSUMER"
+)
+
+// Sentinel domain errors. Adapters should return these (optionally wrapped
+// with fmt.Errorf and %w) so the gRPC layer can classify failures without
+// inspecting error messages.
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrInvalidToken means a call carried no access token, or one that is
+	// malformed, expired or otherwise not accepted
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrInsufficientRights = errors.New("insufficient rights")
+	ErrValidation         = errors.New("validation failed")
+	// ErrNotDeleted means the operation needs a soft-deleted user, e.g.
+	// RestoreUser or PurgeUser on an active one
+	ErrNotDeleted = errors.New("user is not deleted")
+	// ErrETagMismatch means a conditional write was rejected because the
+	// user changed since the caller read it
+	ErrETagMismatch = errors.New("etag mismatch")
+	// ErrRequestInProgress means another request with the same request_id
+	// is still being processed
+	ErrRequestInProgress = errors.New("request in progress")
+	// ErrRevisionCompacted means events after the requested revision are no
+	// longer retained, so a watch cannot resume from it
+	ErrRevisionCompacted = errors.New("revision compacted")
+	// ErrSlowConsumer means a watcher fell too far behind and was dropped
+	ErrSlowConsumer = errors.New("slow consumer")
+	// ErrTokenReused means a refresh token was used after it had been
+	// replaced, so it may have been stolen; its session is ended
+	ErrTokenReused = errors.New("refresh token reused")
+)
+
+// ValidationError reports an invalid value for a single input field.
+// It matches ErrValidation with errors.Is, and several of them may be
+// combined with errors.Join to report more than one field at once.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+// NewValidationError creates a validation error for the given field
+func NewValidationError(field, reason string) *ValidationError {
+	return &ValidationError{Field: field, Reason: reason}
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation failed: %s: %s", e.Field, e.Reason)
+}
+
+// Is makes errors.Is(err, ErrValidation) true for every ValidationError
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// ValidationErrors collects every ValidationError in err's tree, including
+// those combined with errors.Join
+func ValidationErrors(err error) []*ValidationError {
+	var result []*ValidationError
+
+	var walk func(error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		if verr, ok := err.(*ValidationError); ok {
+			result = append(result, verr)
+			return
+		}
+		switch wrapped := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, e := range wrapped.Unwrap() {
+				walk(e)
+			}
+		case interface{ Unwrap() error }:
+			walk(wrapped.Unwrap())
+		}
+	}
+	walk(err)
+
+	return result
+}
+
+// ETagMismatchError reports a conditional write rejected because the user's
+// current etag differs from the expected one. It matches ErrETagMismatch
+// with errors.Is.
+type ETagMismatchError struct {
+	ID          string
+	CurrentETag string
+}
+
+// NewETagMismatchError creates an etag mismatch error for the given user
+func NewETagMismatchError(id, currentETag string) *ETagMismatchError {
+	return &ETagMismatchError{ID: id, CurrentETag: currentETag}
+}
+
+func (e *ETagMismatchError) Error() string {
+	return fmt.Sprintf("etag mismatch: user %s was modified concurrently", e.ID)
+}
+
+// Is makes errors.Is(err, ErrETagMismatch) true for every ETagMismatchError
+func (e *ETagMismatchError) Is(target error) bool {
+	return target == ErrETagMismatch
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CurrentETag returns the user's etag: the adapter-provided ETag if set,
+// otherwise a hash of every field, so that any change yields a new etag
+func (u *UserModel) CurrentETag() string {
+	if u == nil {
+		return ""
+	}
+	if u.ETag != "" {
+		return u.ETag
+	}
+
+	h := sha256.New()
+	for _, field := range []string{
+		u.ID,
+		u.Email,
+		u.FirstName,
+		u.LastName,
+		string(u.Role),
+		strconv.FormatInt(int64(u.Rating), 10),
+		formatETagTimestamp(u.CreatedAt),
+		formatETagTimestamp(u.UpdatedAt),
+		formatETagTimestamp(u.DeletedAt),
+	} {
+		// Length prefixes keep ("ab", "c") and ("a", "bc") apart
+		h.Write([]byte(strconv.Itoa(len(field))))
+		h.Write([]byte{':'})
+		h.Write([]byte(field))
+	}
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:12])
+}
+
+func formatETagTimestamp(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return strconv.FormatInt(ts.GetSeconds(), 10) + "." + strconv.FormatInt(int64(ts.GetNanos()), 10)
+}
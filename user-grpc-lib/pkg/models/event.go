@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type UserEventType string
+
+const (
+	UserEventCreated     UserEventType = "created"
+	UserEventUpdated     UserEventType = "updated"
+	UserEventDeleted     UserEventType = "deleted"
+	UserEventRoleChanged UserEventType = "role_changed"
+)
+
+// UserEvent describes a single change to a user. Revisions are assigned by
+// the event source and increase by one with every event.
+type UserEvent struct {
+	Type       UserEventType
+	User       *UserModel
+	Revision   int64
+	OccurredAt time.Time
+}
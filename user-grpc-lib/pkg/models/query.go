@@ -0,0 +1,152 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Fields users can be ordered by
+const (
+	OrderFieldID        = "id"
+	OrderFieldEmail     = "email"
+	OrderFieldFirstName = "first_name"
+	OrderFieldLastName  = "last_name"
+	OrderFieldRole      = "role"
+	OrderFieldRating    = "rating"
+	OrderFieldCreatedAt = "created_at"
+	OrderFieldUpdatedAt = "updated_at"
+)
+
+var orderFields = map[string]bool{
+	OrderFieldID:        true,
+	OrderFieldEmail:     true,
+	OrderFieldFirstName: true,
+	OrderFieldLastName:  true,
+	OrderFieldRole:      true,
+	OrderFieldRating:    true,
+	OrderFieldCreatedAt: true,
+	OrderFieldUpdatedAt: true,
+}
+
+// UserFilter restricts which users are listed. Zero values mean "no restriction".
+type UserFilter struct {
+	Roles         []Role
+	CreatedAfter  *timestamppb.Timestamp
+	CreatedBefore *timestamppb.Timestamp
+	UpdatedAfter  *timestamppb.Timestamp
+	UpdatedBefore *timestamppb.Timestamp
+	MinRating     *int32
+	MaxRating     *int32
+	// EmailDomain matches the part of the email after "@", case-insensitively
+	EmailDomain string
+	// IncludeDeleted also lists soft-deleted users; OnlyDeleted lists nothing else
+	IncludeDeleted bool
+	OnlyDeleted    bool
+}
+
+// IsZero reports whether the filter has no restrictions
+func (f UserFilter) IsZero() bool {
+	return len(f.Roles) == 0 &&
+		f.CreatedAfter == nil && f.CreatedBefore == nil &&
+		f.UpdatedAfter == nil && f.UpdatedBefore == nil &&
+		f.MinRating == nil && f.MaxRating == nil &&
+		f.EmailDomain == "" && !f.IncludeDeleted && !f.OnlyDeleted
+}
+
+// OrderBy is a single sort key
+type OrderBy struct {
+	Field      string
+	Descending bool
+}
+
+// UserQuery describes filtering and ordering for listing users
+type UserQuery struct {
+	Filter  UserFilter
+	OrderBy []OrderBy
+}
+
+// IsZero reports whether the query neither filters nor orders
+func (q UserQuery) IsZero() bool {
+	return q.Filter.IsZero() && len(q.OrderBy) == 0
+}
+
+// ParseOrderBy parses a comma-separated list of sort keys such as
+// "rating desc, created_at". Keys are ascending unless followed by "desc".
+func ParseOrderBy(orderBy string) ([]OrderBy, error) {
+	if strings.TrimSpace(orderBy) == "" {
+		return nil, nil
+	}
+
+	var result []OrderBy
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(orderBy, ",") {
+		words := strings.Fields(part)
+		if len(words) == 0 || len(words) > 2 {
+			return nil, NewValidationError("order_by", fmt.Sprintf("malformed sort key %q", strings.TrimSpace(part)))
+		}
+
+		field := words[0]
+		if !orderFields[field] {
+			return nil, NewValidationError("order_by", fmt.Sprintf("unsupported field %q", field))
+		}
+		if seen[field] {
+			return nil, NewValidationError("order_by", fmt.Sprintf("field %q listed more than once", field))
+		}
+		seen[field] = true
+
+		key := OrderBy{Field: field}
+		if len(words) == 2 {
+			switch strings.ToLower(words[1]) {
+			case "asc":
+			case "desc":
+				key.Descending = true
+			default:
+				return nil, NewValidationError("order_by", fmt.Sprintf("unsupported direction %q", words[1]))
+			}
+		}
+		result = append(result, key)
+	}
+
+	return result, nil
+}
+
+// Validate checks the filter for contradictory or malformed values and
+// returns every problem found as joined ValidationErrors
+func (f UserFilter) Validate() error {
+	var errs []error
+
+	for _, role := range f.Roles {
+		if role != RoleUser && role != RoleModerator && role != RoleAdmin {
+			errs = append(errs, NewValidationError("filter.roles", fmt.Sprintf("unsupported role %q", role)))
+		}
+	}
+	if err := validateTimeRange("filter.created_after", f.CreatedAfter, f.CreatedBefore); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateTimeRange("filter.updated_after", f.UpdatedAfter, f.UpdatedBefore); err != nil {
+		errs = append(errs, err)
+	}
+	if f.MinRating != nil && f.MaxRating != nil && *f.MinRating > *f.MaxRating {
+		errs = append(errs, NewValidationError("filter.min_rating", "must not exceed max_rating"))
+	}
+	if f.EmailDomain != "" && (strings.Contains(f.EmailDomain, "@") || strings.ContainsAny(f.EmailDomain, " \t")) {
+		errs = append(errs, NewValidationError("filter.email_domain", "must be a bare domain such as example.com"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateTimeRange(field string, after, before *timestamppb.Timestamp) error {
+	for _, ts := range []*timestamppb.Timestamp{after, before} {
+		if ts != nil && !ts.IsValid() {
+			return NewValidationError(field, "is not a valid timestamp")
+		}
+	}
+	if after != nil && before != nil && !after.AsTime().Before(before.AsTime()) {
+		return NewValidationError(field, "must be before the end of the range")
+	}
+	return nil
+}
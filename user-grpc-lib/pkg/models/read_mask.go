@@ -0,0 +1,21 @@
+package models
+
+import "context"
+
+type readMaskKey struct{}
+
+// ContextWithReadMask attaches the user fields a read needs, as field mask
+// paths such as "first_name", to ctx
+func ContextWithReadMask(ctx context.Context, paths []string) context.Context {
+	return context.WithValue(ctx, readMaskKey{}, paths)
+}
+
+// ReadMaskFromContext returns the user fields the current read needs, or
+// nil if it needs all of them. Adapters may use it to skip loading other
+// fields, e.g. expensive columns or joins. It is only a hint: returning
+// more fields is always correct, and the server removes those the client
+// did not ask for.
+func ReadMaskFromContext(ctx context.Context) []string {
+	paths, _ := ctx.Value(readMaskKey{}).([]string)
+	return paths
+}
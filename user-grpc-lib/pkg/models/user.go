@@ -20,9 +20,33 @@ type UserCreateInput struct {
 	LastName  string
 }
 
+// Field mask paths of the user fields UpdateUser can change
+const (
+	UserPathFirstName = "first_name"
+	UserPathLastName  = "last_name"
+	UserPathEmail     = "email"
+	UserPathRating    = "rating"
+)
+
 type UserUpdateInput struct {
 	FirstName *string
 	LastName  *string
+	Email     *string
+	Rating    *int32
+	// Paths lists the fields the caller asked to update, such as
+	// "first_name". Every field in Paths is set, even when it is being
+	// cleared to its zero value, and no other field is.
+	Paths []string
+}
+
+// HasPath reports whether path was requested for update
+func (in UserUpdateInput) HasPath(path string) bool {
+	for _, p := range in.Paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
 }
 
 type UserPasswordUpdateInput struct {
@@ -40,6 +64,9 @@ type UserModel struct {
 	UpdatedAt *timestamppb.Timestamp
 	DeletedAt *timestamppb.Timestamp
 	Rating    int32
+	// ETag is an opaque version set by adapters that track one (e.g. a
+	// version column). When empty, CurrentETag derives one from the fields.
+	ETag string
 }
 
 type PaginatedUsersModel struct {
@@ -49,3 +76,28 @@ type PaginatedUsersModel struct {
 	PageSize   int64
 	TotalPages int64
 }
+
+type LoginResult struct {
+	Token     string
+	TokenType string
+	ExpiresAt *timestamppb.Timestamp
+	User      *UserModel
+	// Tenant is the tenant the user logged in to, if any
+	Tenant string
+}
+
+type UserCursorPageModel struct {
+	Users      []*UserModel
+	NextCursor string
+	Total      int64
+}
+
+type UserSearchResult struct {
+	User  *UserModel
+	Score float64
+}
+
+type UserSearchPageModel struct {
+	Results    []*UserSearchResult
+	NextCursor string
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/token"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// AuthorizationMetadataKey is the metadata key carrying "Bearer <token>"
+const AuthorizationMetadataKey = "authorization"
+
+// DefaultPublicMethods are the full method names AuthInterceptor serves
+// without a token: Login and RefreshToken, which issue tokens,
+// GetSigningKeys, which publishes the keys to verify them, and the health
+// service
+var DefaultPublicMethods = []string{
+	pb.UserService_Login_FullMethodName,
+	pb.UserService_RefreshToken_FullMethodName,
+	pb.UserService_GetSigningKeys_FullMethodName,
+	"/grpc.health.v1.Health/Check",
+	"/grpc.health.v1.Health/Watch",
+}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	ID     string
+	Role   models.Role
+	Tenant string
+}
+
+// TokenVerifier checks an access token and returns the principal it was
+// issued to. Any error rejects the call with codes.Unauthenticated; the
+// error itself is not sent to the client.
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context, token string) (*Principal, error)
+}
+
+// NewJWTVerifier adapts a token.Verifier to TokenVerifier. The principal is
+// the token's subject, with the role and tenant claims of the token. Tokens
+// revoked in revocations, which should be the store given to
+// WithRevocationStore, are rejected; a nil store skips revocation checks.
+func NewJWTVerifier(verifier *token.Verifier, revocations RevocationStore) TokenVerifier {
+	return TokenVerifierFunc(func(ctx context.Context, raw string) (*Principal, error) {
+		claims, err := verifyJWT(ctx, verifier, revocations, raw)
+		if err != nil {
+			return nil, err
+		}
+		return principalFromClaims(claims), nil
+	})
+}
+
+// TokenVerifierFunc adapts a function to TokenVerifier
+type TokenVerifierFunc func(ctx context.Context, token string) (*Principal, error)
+
+// VerifyToken implements TokenVerifier
+func (f TokenVerifierFunc) VerifyToken(ctx context.Context, token string) (*Principal, error) {
+	return f(ctx, token)
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal attaches the authenticated caller to ctx
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the caller authenticated by AuthInterceptor.
+// Adapters can use it to scope their queries, e.g. by Tenant.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// AuthInterceptor authenticates every call with the bearer token in its
+// metadata and places the resulting Principal in the call's context.
+// Calls to other than the public methods without a valid token fail with
+// codes.Unauthenticated.
+type AuthInterceptor struct {
+	verifier TokenVerifier
+	public   map[string]bool
+}
+
+// NewAuthInterceptor creates an interceptor verifying tokens with verifier.
+// publicMethods are full method names served without a token, such as
+// "/user.v1.UserService/CreateUser"; DefaultPublicMethods are always public.
+func NewAuthInterceptor(verifier TokenVerifier, publicMethods ...string) *AuthInterceptor {
+	public := make(map[string]bool, len(DefaultPublicMethods)+len(publicMethods))
+	for _, method := range DefaultPublicMethods {
+		public[method] = true
+	}
+	for _, method := range publicMethods {
+		public[method] = true
+	}
+
+	return &AuthInterceptor{
+		verifier: verifier,
+		public:   public,
+	}
+}
+
+// Unary returns the interceptor for unary methods
+func (a *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns the interceptor for streaming methods
+func (a *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate verifies the bearer token of a call to method. Public
+// methods are let through without one, but still get a principal when a
+// valid token is sent.
+func (a *AuthInterceptor) authenticate(ctx context.Context, method string) (context.Context, error) {
+	raw, ok := bearerToken(ctx)
+	if !ok {
+		if a.public[method] {
+			return ctx, nil
+		}
+		return nil, newStatusError(codes.Unauthenticated, "missing bearer token", models.ReasonInvalidToken, nil, nil)
+	}
+
+	principal, err := a.verifier.VerifyToken(ctx, raw)
+	if err != nil || principal == nil || principal.ID == "" {
+		return nil, newStatusError(codes.Unauthenticated, "invalid bearer token", models.ReasonInvalidToken, nil, nil)
+	}
+	return ContextWithPrincipal(ctx, principal), nil
+}
+
+// bearerToken extracts the token from the call's authorization metadata
+func bearerToken(ctx context.Context) (string, bool) {
+	values := metadata.ValueFromIncomingContext(ctx, AuthorizationMetadataKey)
+	if len(values) == 0 {
+		return "", false
+	}
+
+	scheme, raw, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	raw = strings.TrimSpace(raw)
+	return raw, raw != ""
+}
+
+// authenticatedStream overrides the context of a server stream
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// resolveActor returns the actor of a request. When the call was
+// authenticated the actor is the principal, and request-supplied actor
+// fields must either be empty or match it. Otherwise the request fields
+// are trusted as is, which is only safe behind a trusted gateway.
+func (s *UserServiceServer) resolveActor(ctx context.Context, actorID string, actorRole pb.Role) (string, pb.Role, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return actorID, actorRole, nil
+	}
+
+	role := s.converter.ConvertRoleToProto(principal.Role)
+	if role == pb.Role_ROLE_UNSPECIFIED {
+		return "", role, fmt.Errorf("authenticated user has no known role: %w", models.ErrInsufficientRights)
+	}
+	if actorID != "" && actorID != principal.ID {
+		return "", role, fmt.Errorf("actor_id does not match the authenticated user: %w", models.ErrInsufficientRights)
+	}
+	if actorRole != pb.Role_ROLE_UNSPECIFIED && actorRole != role {
+		return "", role, fmt.Errorf("actor_role does not match the authenticated user: %w", models.ErrInsufficientRights)
+	}
+	return principal.ID, role, nil
+}
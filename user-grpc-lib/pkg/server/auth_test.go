@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/token"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+var testVerifier = TokenVerifierFunc(func(ctx context.Context, token string) (*Principal, error) {
+	if token != "valid" {
+		return nil, errors.New("bad signature")
+	}
+	return &Principal{ID: "admin-1", Role: models.RoleAdmin, Tenant: "acme"}, nil
+})
+
+func incomingContext(authorization string) context.Context {
+	md := metadata.MD{}
+	if authorization != "" {
+		md.Set(AuthorizationMetadataKey, authorization)
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestAuthInterceptor_Unary(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		expectedError codes.Code
+		expectedID    string
+	}{
+		{name: "valid token", method: pb.UserService_GetUserByID_FullMethodName, authorization: "Bearer valid", expectedID: "admin-1"},
+		{name: "scheme is case-insensitive", method: pb.UserService_GetUserByID_FullMethodName, authorization: "bearer valid", expectedID: "admin-1"},
+		{name: "missing token", method: pb.UserService_GetUserByID_FullMethodName, expectedError: codes.Unauthenticated},
+		{name: "wrong scheme", method: pb.UserService_GetUserByID_FullMethodName, authorization: "Basic dXNlcjpwYXNz", expectedError: codes.Unauthenticated},
+		{name: "invalid token", method: pb.UserService_GetUserByID_FullMethodName, authorization: "Bearer forged", expectedError: codes.Unauthenticated},
+		{name: "public method without token", method: pb.UserService_Login_FullMethodName},
+		{name: "public method with token", method: pb.UserService_Login_FullMethodName, authorization: "Bearer valid", expectedID: "admin-1"},
+		{name: "configured public method", method: pb.UserService_CreateUser_FullMethodName},
+		{name: "invalid token on public method", method: pb.UserService_Login_FullMethodName, authorization: "Bearer forged", expectedError: codes.Unauthenticated},
+	}
+
+	interceptor := NewAuthInterceptor(testVerifier, pb.UserService_CreateUser_FullMethodName).Unary()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var principal *Principal
+			handler := func(ctx context.Context, req any) (any, error) {
+				principal, _ = PrincipalFromContext(ctx)
+				return "ok", nil
+			}
+
+			resp, err := interceptor(incomingContext(tt.authorization), nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			if tt.expectedError != codes.OK {
+				assert.Equal(t, tt.expectedError, status.Code(err))
+				assert.NotContains(t, err.Error(), "bad signature")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ok", resp)
+			if tt.expectedID == "" {
+				assert.Nil(t, principal)
+			} else {
+				require.NotNil(t, principal)
+				assert.Equal(t, tt.expectedID, principal.ID)
+				assert.Equal(t, "acme", principal.Tenant)
+			}
+		})
+	}
+}
+
+// fakeServerStream is a grpc.ServerStream with a fixed context
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
+
+func TestAuthInterceptor_Stream(t *testing.T) {
+	interceptor := NewAuthInterceptor(testVerifier).Stream()
+	info := &grpc.StreamServerInfo{FullMethod: pb.UserService_WatchUsers_FullMethodName}
+
+	var principal *Principal
+	handler := func(srv any, ss grpc.ServerStream) error {
+		principal, _ = PrincipalFromContext(ss.Context())
+		return nil
+	}
+
+	err := interceptor(nil, &fakeServerStream{ctx: incomingContext("Bearer valid")}, info, handler)
+	require.NoError(t, err)
+	require.NotNil(t, principal)
+	assert.Equal(t, "admin-1", principal.ID)
+
+	err = interceptor(nil, &fakeServerStream{ctx: incomingContext("")}, info, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestUserServiceServer_DeleteUser_Principal(t *testing.T) {
+	principal := &Principal{ID: "admin-1", Role: models.RoleAdmin}
+
+	tests := []struct {
+		name          string
+		request       *pb.DeleteUserRequest
+		mockSetup     func(*MockUserService)
+		expectedError codes.Code
+	}{
+		{
+			name:    "actor taken from principal",
+			request: &pb.DeleteUserRequest{Id: "123"},
+			mockSetup: func(m *MockUserService) {
+				m.On("DeleteUser", mock.Anything, "123", "admin-1", models.RoleAdmin).Return(nil)
+			},
+		},
+		{
+			name:    "matching actor fields accepted",
+			request: &pb.DeleteUserRequest{Id: "123", ActorId: "admin-1", ActorRole: pb.Role_ROLE_ADMIN},
+			mockSetup: func(m *MockUserService) {
+				m.On("DeleteUser", mock.Anything, "123", "admin-1", models.RoleAdmin).Return(nil)
+			},
+		},
+		{
+			name:          "mismatched actor id rejected",
+			request:       &pb.DeleteUserRequest{Id: "123", ActorId: "someone-else"},
+			mockSetup:     func(m *MockUserService) {},
+			expectedError: codes.PermissionDenied,
+		},
+		{
+			name:          "mismatched actor role rejected",
+			request:       &pb.DeleteUserRequest{Id: "123", ActorRole: pb.Role_ROLE_USER},
+			mockSetup:     func(m *MockUserService) {},
+			expectedError: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserService{}
+			tt.mockSetup(mockService)
+
+			server := newTestServer(t, mockService)
+			ctx := ContextWithPrincipal(context.Background(), principal)
+
+			_, err := server.DeleteUser(ctx, tt.request)
+
+			assert.Equal(t, tt.expectedError, status.Code(err))
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServiceServer_UpdateUserRole_Principal(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("UpdateUserRole", mock.Anything, "123", models.RoleModerator, models.RoleAdmin).Return(nil).Once()
+
+	server := newTestServer(t, mockService)
+	ctx := ContextWithPrincipal(context.Background(), &Principal{ID: "admin-1", Role: models.RoleAdmin})
+
+	_, err := server.UpdateUserRole(ctx, &pb.UpdateUserRoleRequest{Id: "123", Role: pb.Role_ROLE_MODERATOR})
+	require.NoError(t, err)
+
+	// A moderator cannot claim to be an admin
+	ctx = ContextWithPrincipal(context.Background(), &Principal{ID: "mod-1", Role: models.RoleModerator})
+	_, err = server.UpdateUserRole(ctx, &pb.UpdateUserRoleRequest{Id: "123", Role: pb.Role_ROLE_ADMIN, ActorRole: pb.Role_ROLE_ADMIN})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_PurgeUser_Principal(t *testing.T) {
+	mockService := &MockLifecycleUserService{}
+	server := newTestServer(t, mockService)
+
+	// The request claims admin, but the caller is only a moderator
+	ctx := ContextWithPrincipal(context.Background(), &Principal{ID: "mod-1", Role: models.RoleModerator})
+	_, err := server.PurgeUser(ctx, &pb.PurgeUserRequest{Id: "123", ActorId: "mod-1", ActorRole: pb.Role_ROLE_ADMIN})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = server.PurgeUser(ctx, &pb.PurgeUserRequest{Id: "123"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNewJWTVerifier(t *testing.T) {
+	key, err := token.GenerateKey("k1", token.EdDSA)
+	require.NoError(t, err)
+	keys, err := token.NewKeySet(key)
+	require.NoError(t, err)
+
+	// Issued a second ago, so that RevokeAllForUser below covers it
+	issuedAt := time.Now().Add(-time.Second)
+	raw, _, err := token.NewIssuer(keys, token.WithAudience("users"), token.WithClock(func() time.Time { return issuedAt })).Issue(token.Claims{
+		Subject: "123",
+		Role:    string(models.RoleModerator),
+		Tenant:  "acme",
+	})
+	require.NoError(t, err)
+
+	revocations := NewMemoryRevocationStore()
+	verifier := NewJWTVerifier(token.NewVerifier(keys, token.WithAudience("users")), revocations)
+	principal, err := verifier.VerifyToken(context.Background(), raw)
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{ID: "123", Role: models.RoleModerator, Tenant: "acme"}, principal)
+
+	_, err = NewJWTVerifier(token.NewVerifier(keys, token.WithAudience("billing")), nil).VerifyToken(context.Background(), raw)
+	assert.ErrorIs(t, err, token.ErrInvalidAudience)
+
+	// Revocations through a server sharing the store are honoured by the
+	// interceptor
+	server := newTestServer(t, &MockUserService{}, WithRevocationStore(revocations))
+	_, err = server.RevokeAllForUser(context.Background(), &pb.RevokeAllForUserRequest{UserId: "123"})
+	require.NoError(t, err)
+
+	interceptor := NewAuthInterceptor(verifier).Unary()
+	info := &grpc.UnaryServerInfo{FullMethod: pb.UserService_GetUserByID_FullMethodName}
+	_, err = interceptor(incomingContext("Bearer "+raw), nil, info, func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
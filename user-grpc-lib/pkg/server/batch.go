@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+)
+
+const (
+	// DefaultMaxBatchSize is the largest number of distinct IDs BatchGetUsers
+	// accepts unless overridden with WithMaxBatchSize
+	DefaultMaxBatchSize = 500
+	// DefaultBatchParallelism bounds the concurrent GetUserByID calls made by
+	// BatchGetUsers for adapters that do not implement BatchGetter
+	DefaultBatchParallelism = 8
+)
+
+// BatchGetter is an optional extension of UserServiceInterface for adapters
+// that can load many users in one query. IDs that do not exist are simply
+// left out of the returned map.
+type BatchGetter interface {
+	GetUsersByIDs(ctx context.Context, ids []string) (map[string]*models.UserModel, error)
+}
+
+// getUsersByIDs loads users through the adapter's BatchGetter, or with
+// concurrent GetUserByID calls when it does not implement one. Users that
+// are not found are left out of the result; any other failure aborts the
+// whole batch.
+func (s *UserServiceServer) getUsersByIDs(ctx context.Context, ids []string) (map[string]*models.UserModel, error) {
+	if batchGetter, ok := s.userService.(BatchGetter); ok {
+		return batchGetter.GetUsersByIDs(ctx, ids)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		users    = make(map[string]*models.UserModel, len(ids))
+		sem      = make(chan struct{}, s.batchParallelism)
+	)
+
+	for _, id := range ids {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			user, err := s.userService.GetUserByID(ctx, id)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil && user != nil:
+				users[id] = user
+			case err == nil || status.Code(s.convertError(err)) == codes.NotFound:
+			case firstErr == nil:
+				firstErr = err
+				cancel()
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// dedupeIDs removes duplicate IDs while preserving their first-seen order
+func dedupeIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
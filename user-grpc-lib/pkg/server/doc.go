@@ -0,0 +1,9 @@
+// Package server implements the gRPC UserService on top of an adapter
+// implementing UserServiceInterface.
+//
+// Replicas behind a load balancer must share their state: the same page
+// token key (WithPageTokenKey) and idempotency key (WithIdempotencyKey), and
+// stores all of them reach for request IDs (WithIdempotencyStore), revoked
+// tokens (WithRevocationStore) and sessions (WithSessionStore). The default
+// random keys and in-memory stores only suit a single replica.
+package server
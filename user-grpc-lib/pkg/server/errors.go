@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+)
+
+// domainErrors maps sentinel domain errors to gRPC codes and ErrorInfo reasons
+var domainErrors = []struct {
+	target error
+	code   codes.Code
+	reason string
+}{
+	{models.ErrNotFound, codes.NotFound, models.ReasonNotFound},
+	{models.ErrAlreadyExists, codes.AlreadyExists, models.ReasonAlreadyExists},
+	{models.ErrInvalidCredentials, codes.Unauthenticated, models.ReasonInvalidCredentials},
+	{models.ErrInvalidToken, codes.Unauthenticated, models.ReasonInvalidToken},
+	{models.ErrTokenReused, codes.Unauthenticated, models.ReasonTokenReused},
+	{models.ErrInsufficientRights, codes.PermissionDenied, models.ReasonInsufficientRights},
+	{models.ErrNotDeleted, codes.FailedPrecondition, models.ReasonNotDeleted},
+	{models.ErrETagMismatch, codes.Aborted, models.ReasonETagMismatch},
+	{models.ErrRequestInProgress, codes.Aborted, models.ReasonRequestInProgress},
+	{models.ErrRevisionCompacted, codes.OutOfRange, models.ReasonRevisionCompacted},
+	{models.ErrSlowConsumer, codes.ResourceExhausted, models.ReasonSlowConsumer},
+}
+
+// legacyErrors maps message substrings to gRPC codes for adapters that
+// predate the typed errors in the models package
+var legacyErrors = []struct {
+	substr string
+	code   codes.Code
+}{
+	{"not found", codes.NotFound},
+	{"already exists", codes.AlreadyExists},
+	{"invalid credentials", codes.Unauthenticated},
+	{"insufficient rights", codes.PermissionDenied},
+	{"validation", codes.InvalidArgument},
+}
+
+// convertError converts domain errors to appropriate gRPC status codes.
+// Typed errors from the models package are matched with errors.Is/As and
+// carry ErrorInfo and BadRequest details; anything else falls back to the
+// legacy message matching. The status message of a typed error is the fixed
+// message of its sentinel, so that whatever an adapter wrapped around it,
+// such as driver or SQL errors, never reaches the client.
+func (s *UserServiceServer) convertError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Errors that already carry a status (e.g. context cancellation mapped
+	// by the adapter) are passed through untouched
+	if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return err
+	}
+
+	// Adapters usually surface cancellation of the call's context as-is
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	}
+
+	if errors.Is(err, models.ErrValidation) {
+		return newStatusError(codes.InvalidArgument, models.ErrValidation.Error(), models.ReasonValidationFailed, models.ValidationErrors(err), nil)
+	}
+
+	// Clients retry a conditional write by re-reading the user, or directly
+	// with the current etag reported in the ErrorInfo metadata
+	var mismatch *models.ETagMismatchError
+	if errors.As(err, &mismatch) {
+		return newStatusError(codes.Aborted, models.ErrETagMismatch.Error(), models.ReasonETagMismatch, nil, map[string]string{
+			models.MetadataCurrentETag: mismatch.CurrentETag,
+		})
+	}
+
+	for _, de := range domainErrors {
+		if errors.Is(err, de.target) {
+			return newStatusError(de.code, de.target.Error(), de.reason, nil, nil)
+		}
+	}
+
+	return convertLegacyError(err)
+}
+
+// convertLegacyError classifies untyped errors by their message. Unmatched
+// errors become codes.Internal without exposing the underlying message.
+func convertLegacyError(err error) error {
+	errMsg := err.Error()
+
+	for _, le := range legacyErrors {
+		if strings.Contains(errMsg, le.substr) {
+			return status.Error(le.code, errMsg)
+		}
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+// newStatusError builds a status error with an ErrorInfo detail carrying
+// metadata and, when violations are given, a BadRequest detail listing them
+func newStatusError(code codes.Code, msg, reason string, violations []*models.ValidationError, metadata map[string]string) error {
+	st := status.New(code, msg)
+
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   reason,
+			Domain:   models.ErrorDomain,
+			Metadata: metadata,
+		},
+	}
+	if len(violations) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, v := range violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Reason,
+			})
+		}
+		details = append(details, badRequest)
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+)
+
+// ConditionalWriter is an optional extension of UserServiceInterface for
+// adapters that can compare the expected etag and write in a single atomic
+// step (e.g. UPDATE ... WHERE version = ?). On mismatch they return a
+// *models.ETagMismatchError carrying the current etag.
+//
+// For adapters that do not implement it, the server reads the user with
+// GetUserByID and compares etags before writing. That catches edits based
+// on stale reads but leaves a short window for writes racing the check.
+type ConditionalWriter interface {
+	UpdateUserIfMatch(ctx context.Context, id string, input models.UserUpdateInput, expectedETag string) (*models.UserModel, error)
+	DeleteUserIfMatch(ctx context.Context, id string, actorID string, actorRole models.Role, expectedETag string) error
+	UpdateUserRoleIfMatch(ctx context.Context, id string, role models.Role, actorRole models.Role, expectedETag string) error
+}
+
+// updateUserIfMatch updates a user, only if its etag matches expectedETag when set
+func (s *UserServiceServer) updateUserIfMatch(ctx context.Context, id string, input models.UserUpdateInput, expectedETag string) (*models.UserModel, error) {
+	if expectedETag == "" {
+		return s.userService.UpdateUser(ctx, id, input)
+	}
+	if writer, ok := s.userService.(ConditionalWriter); ok {
+		return writer.UpdateUserIfMatch(ctx, id, input, expectedETag)
+	}
+	if err := s.checkETag(ctx, id, expectedETag); err != nil {
+		return nil, err
+	}
+	return s.userService.UpdateUser(ctx, id, input)
+}
+
+// deleteUserIfMatch deletes a user, only if its etag matches expectedETag when set
+func (s *UserServiceServer) deleteUserIfMatch(ctx context.Context, id, actorID string, actorRole models.Role, expectedETag string) error {
+	if expectedETag == "" {
+		return s.userService.DeleteUser(ctx, id, actorID, actorRole)
+	}
+	if writer, ok := s.userService.(ConditionalWriter); ok {
+		return writer.DeleteUserIfMatch(ctx, id, actorID, actorRole, expectedETag)
+	}
+	if err := s.checkETag(ctx, id, expectedETag); err != nil {
+		return err
+	}
+	return s.userService.DeleteUser(ctx, id, actorID, actorRole)
+}
+
+// updateUserRoleIfMatch changes a user's role, only if its etag matches
+// expectedETag when set
+func (s *UserServiceServer) updateUserRoleIfMatch(ctx context.Context, id string, role, actorRole models.Role, expectedETag string) error {
+	if expectedETag == "" {
+		return s.userService.UpdateUserRole(ctx, id, role, actorRole)
+	}
+	if writer, ok := s.userService.(ConditionalWriter); ok {
+		return writer.UpdateUserRoleIfMatch(ctx, id, role, actorRole, expectedETag)
+	}
+	if err := s.checkETag(ctx, id, expectedETag); err != nil {
+		return err
+	}
+	return s.userService.UpdateUserRole(ctx, id, role, actorRole)
+}
+
+// checkETag returns a *models.ETagMismatchError unless the user's current
+// etag is expectedETag
+func (s *UserServiceServer) checkETag(ctx context.Context, id, expectedETag string) error {
+	user, err := s.userService.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if current := user.CurrentETag(); current != expectedETag {
+		return models.NewETagMismatchError(id, current)
+	}
+	return nil
+}
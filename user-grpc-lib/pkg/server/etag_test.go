@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// MockConditionalUserService additionally implements ConditionalWriter
+type MockConditionalUserService struct {
+	MockUserService
+}
+
+func (m *MockConditionalUserService) UpdateUserIfMatch(ctx context.Context, id string, input models.UserUpdateInput, expectedETag string) (*models.UserModel, error) {
+	args := m.Called(ctx, id, input, expectedETag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserModel), args.Error(1)
+}
+
+func (m *MockConditionalUserService) DeleteUserIfMatch(ctx context.Context, id string, actorID string, actorRole models.Role, expectedETag string) error {
+	args := m.Called(ctx, id, actorID, actorRole, expectedETag)
+	return args.Error(0)
+}
+
+func (m *MockConditionalUserService) UpdateUserRoleIfMatch(ctx context.Context, id string, role models.Role, actorRole models.Role, expectedETag string) error {
+	args := m.Called(ctx, id, role, actorRole, expectedETag)
+	return args.Error(0)
+}
+
+func TestUserModel_CurrentETag(t *testing.T) {
+	user := &models.UserModel{ID: "1", FirstName: "John", UpdatedAt: timestamppb.Now()}
+	etag := user.CurrentETag()
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, etag, user.CurrentETag())
+
+	changed := *user
+	changed.FirstName = "Johnny"
+	assert.NotEqual(t, etag, changed.CurrentETag())
+
+	changed.ETag = "7"
+	assert.Equal(t, "7", changed.CurrentETag())
+
+	converted := NewModelConverter().ConvertUserToProto(user)
+	assert.Equal(t, etag, converted.Etag)
+}
+
+func assertCurrentETag(t *testing.T, err error, expected string) {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Aborted, st.Code())
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			assert.Equal(t, models.ReasonETagMismatch, info.Reason)
+			assert.Equal(t, expected, info.Metadata[models.MetadataCurrentETag])
+			return
+		}
+	}
+	t.Fatal("missing ErrorInfo detail")
+}
+
+func TestUserServiceServer_UpdateUser_ETagFallback(t *testing.T) {
+	current := &models.UserModel{ID: "123", FirstName: "John"}
+	firstName := "Jane"
+	input := models.UserUpdateInput{FirstName: &firstName, Paths: []string{models.UserPathFirstName}}
+
+	mockService := &MockUserService{}
+	mockService.On("GetUserByID", mock.Anything, "123").Return(current, nil)
+	mockService.On("UpdateUser", mock.Anything, "123", input).Return(&models.UserModel{ID: "123", FirstName: "Jane"}, nil).Once()
+
+	server := newTestServer(t, mockService)
+
+	_, err := server.UpdateUser(context.Background(), &pb.UpdateUserRequest{Id: "123", FirstName: &firstName, ExpectedEtag: "stale"})
+	assertCurrentETag(t, err, current.CurrentETag())
+
+	resp, err := server.UpdateUser(context.Background(), &pb.UpdateUserRequest{Id: "123", FirstName: &firstName, ExpectedEtag: current.CurrentETag()})
+	require.NoError(t, err)
+	assert.NotEqual(t, current.CurrentETag(), resp.User.Etag)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_DeleteUser_ETagFallback(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("GetUserByID", mock.Anything, "123").Return(&models.UserModel{ID: "123", ETag: "5"}, nil)
+
+	server := newTestServer(t, mockService)
+
+	_, err := server.DeleteUser(context.Background(), &pb.DeleteUserRequest{
+		Id: "123", ActorId: "admin-1", ActorRole: pb.Role_ROLE_ADMIN, ExpectedEtag: "4",
+	})
+
+	assertCurrentETag(t, err, "5")
+	mockService.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUserServiceServer_ConditionalWriter(t *testing.T) {
+	mockService := &MockConditionalUserService{}
+	mockService.On("UpdateUserRoleIfMatch", mock.Anything, "123", models.RoleModerator, models.RoleAdmin, "4").
+		Return(models.NewETagMismatchError("123", "5"))
+	mockService.On("DeleteUserIfMatch", mock.Anything, "123", "admin-1", models.RoleAdmin, "5").Return(nil)
+
+	server := newTestServer(t, mockService)
+
+	_, err := server.UpdateUserRole(context.Background(), &pb.UpdateUserRoleRequest{
+		Id: "123", Role: pb.Role_ROLE_MODERATOR, ActorRole: pb.Role_ROLE_ADMIN, ExpectedEtag: "4",
+	})
+	assertCurrentETag(t, err, "5")
+
+	resp, err := server.DeleteUser(context.Background(), &pb.DeleteUserRequest{
+		Id: "123", ActorId: "admin-1", ActorRole: pb.Role_ROLE_ADMIN, ExpectedEtag: "5",
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+}
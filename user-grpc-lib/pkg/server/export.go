@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// UserExporter is an optional extension of UserServiceInterface for adapters
+// that can stream every matching user from a consistent snapshot (e.g. a
+// single read-only transaction). The adapter calls emit once per user,
+// together with an opaque cursor from which the export can resume after
+// that user, and must stop and return the error if emit fails. An empty
+// cursor argument starts from the beginning.
+//
+// Adapters that do not implement it are exported page by page through
+// CursorLister, FilteredLister or ListUsers, in that order of preference.
+type UserExporter interface {
+	ExportUsers(ctx context.Context, query models.UserQuery, cursor string, emit func(user *models.UserModel, cursor string) error) error
+}
+
+// exportUsers streams every user matching query, starting after the
+// position recorded in resume, to emit
+func (s *UserServiceServer) exportUsers(ctx context.Context, query models.UserQuery, resume pageToken, emit func(*models.UserModel, pageToken) error) error {
+	fingerprint := resume.Query
+
+	if exporter, ok := s.userService.(UserExporter); ok {
+		return exporter.ExportUsers(ctx, query, resume.Cursor, func(user *models.UserModel, cursor string) error {
+			return emit(user, pageToken{Cursor: cursor, Query: fingerprint})
+		})
+	}
+
+	pageSize := s.maxPageSize
+
+	// Each page is re-read from its own position, skipping users that were
+	// already sent, so a resume token points at the page holding the user
+	// plus the number of users of that page already exported
+	if lister, ok := s.userService.(CursorLister); ok {
+		cursor, skip := resume.Cursor, resume.Skip
+		for {
+			page, err := lister.ListUsersCursor(ctx, query, cursor, pageSize)
+			if err != nil {
+				return err
+			}
+			for i := skip; i < int64(len(page.Users)); i++ {
+				if !matchesDeletedFilter(query.Filter, page.Users[i]) {
+					continue
+				}
+				if err := emit(page.Users[i], pageToken{Cursor: cursor, Skip: i + 1, Query: fingerprint}); err != nil {
+					return err
+				}
+			}
+			if page.NextCursor == "" {
+				return nil
+			}
+			cursor, skip = page.NextCursor, 0
+		}
+	}
+
+	list := &listUsersRequest{query: query, pageSize: pageSize}
+	pageNumber, skip := max(resume.Page, 1), resume.Skip
+	for {
+		page, err := s.listUsersPage(ctx, list, pageNumber)
+		if err != nil {
+			return err
+		}
+		for i := skip; i < int64(len(page.Users)); i++ {
+			if !matchesDeletedFilter(query.Filter, page.Users[i]) {
+				continue
+			}
+			if err := emit(page.Users[i], pageToken{Page: pageNumber, Skip: i + 1, Query: fingerprint}); err != nil {
+				return err
+			}
+		}
+		if len(page.Users) == 0 || pageNumber >= page.TotalPages {
+			return nil
+		}
+		pageNumber, skip = pageNumber+1, 0
+	}
+}
+
+// matchesDeletedFilter applies the soft-delete switches of filter in memory,
+// for adapters whose paging methods ignore them
+func matchesDeletedFilter(filter models.UserFilter, user *models.UserModel) bool {
+	deleted := user.DeletedAt != nil
+	switch {
+	case filter.OnlyDeleted:
+		return deleted
+	case filter.IncludeDeleted:
+		return true
+	default:
+		return !deleted
+	}
+}
+
+// sendExportedUser sends a single user and its resume token on stream
+func (s *UserServiceServer) sendExportedUser(stream pb.UserService_ExportUsersServer, user *models.UserModel, resume pageToken) error {
+	token, err := s.pageTokens.encode(resume)
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&pb.ExportUsersResponse{
+		User:        s.converter.ConvertUserToProto(user),
+		ResumeToken: token,
+	})
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// DefaultHealthCheckInterval is how often HealthServer pings the adapter
+const DefaultHealthCheckInterval = 10 * time.Second
+
+// Pinger is an optional extension of UserServiceInterface for adapters that
+// can report whether their dependencies (database, cache, ...) are reachable.
+// Adapters that do not implement it are always reported as SERVING.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// HealthServer implements the standard grpc.health.v1.Health service for
+// UserService. The serving status is refreshed periodically from the
+// adapter's Pinger and is reported both for the overall server ("") and for
+// the "user.v1.UserService" service name.
+type HealthServer struct {
+	*health.Server
+	pinger   Pinger
+	interval time.Duration
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewHealthServer creates a health server for the given adapter. A
+// non-positive interval uses DefaultHealthCheckInterval.
+func NewHealthServer(userService UserServiceInterface, interval time.Duration) *HealthServer {
+	if interval <= 0 {
+		interval = DefaultHealthCheckInterval
+	}
+
+	pinger, _ := userService.(Pinger)
+
+	h := &HealthServer{
+		Server:   health.NewServer(),
+		pinger:   pinger,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+	h.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	return h
+}
+
+// Register registers the health service on the given gRPC server
+func (h *HealthServer) Register(s grpc.ServiceRegistrar) {
+	healthpb.RegisterHealthServer(s, h)
+}
+
+// Start performs an initial check and then keeps the serving status up to
+// date in the background until Stop is called
+func (h *HealthServer) Start() {
+	h.Update(context.Background())
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-h.stop:
+				return
+			case <-ticker.C:
+				h.Update(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop stops the background checks and permanently reports NOT_SERVING,
+// which lets clients drain before the server shuts down
+func (h *HealthServer) Stop() {
+	h.stopOnce.Do(func() {
+		close(h.stop)
+	})
+	h.wg.Wait()
+	h.Shutdown()
+}
+
+// Update pings the adapter once and updates the serving status accordingly
+func (h *HealthServer) Update(ctx context.Context) {
+	if h.pinger == nil {
+		h.setStatus(healthpb.HealthCheckResponse_SERVING)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.interval)
+	defer cancel()
+
+	if err := h.pinger.Ping(ctx); err != nil {
+		h.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+		return
+	}
+	h.setStatus(healthpb.HealthCheckResponse_SERVING)
+}
+
+func (h *HealthServer) setStatus(servingStatus healthpb.HealthCheckResponse_ServingStatus) {
+	h.SetServingStatus("", servingStatus)
+	h.SetServingStatus(pb.UserService_ServiceDesc.ServiceName, servingStatus)
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// MockPingingUserService additionally implements Pinger
+type MockPingingUserService struct {
+	MockUserService
+}
+
+func (m *MockPingingUserService) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func checkHealth(t *testing.T, h *HealthServer, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+
+	resp, err := h.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	assert.NoError(t, err)
+	return resp.Status
+}
+
+func TestHealthServer_WithoutPinger(t *testing.T) {
+	h := NewHealthServer(&MockUserService{}, 0)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkHealth(t, h, ""))
+
+	h.Update(context.Background())
+
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, checkHealth(t, h, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, checkHealth(t, h, pb.UserService_ServiceDesc.ServiceName))
+}
+
+func TestHealthServer_FollowsPinger(t *testing.T) {
+	mockService := &MockPingingUserService{}
+	mockService.On("Ping", mock.Anything).Return(errors.New("database unreachable")).Once()
+	mockService.On("Ping", mock.Anything).Return(nil).Once()
+
+	h := NewHealthServer(mockService, 0)
+
+	h.Update(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkHealth(t, h, pb.UserService_ServiceDesc.ServiceName))
+
+	h.Update(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, checkHealth(t, h, pb.UserService_ServiceDesc.ServiceName))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestHealthServer_StopReportsNotServing(t *testing.T) {
+	h := NewHealthServer(&MockUserService{}, 0)
+	h.Start()
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, checkHealth(t, h, ""))
+
+	h.Stop()
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkHealth(t, h, ""))
+	h.Update(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkHealth(t, h, ""))
+}
@@ -0,0 +1,262 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+)
+
+const (
+	// DefaultIdempotencyTTL is how long the default store remembers request IDs
+	DefaultIdempotencyTTL = 24 * time.Hour
+	// MaxRequestIDLength is the longest request_id accepted, in bytes
+	MaxRequestIDLength = 128
+	// MinIdempotencyKeySize is the shortest key WithIdempotencyKey accepts,
+	// in bytes
+	MinIdempotencyKeySize = 32
+)
+
+// IdempotencyRecord is what an IdempotencyStore keeps per request ID
+type IdempotencyRecord struct {
+	// Fingerprint identifies the request payload, so that a request ID
+	// reused for a different request can be rejected
+	Fingerprint string
+	// Response is the marshaled response of the completed request, or nil
+	// while the request is still being processed
+	Response []byte
+}
+
+// IdempotencyStore remembers the outcome of mutating requests by request
+// ID. Records hold an HMAC of the request keyed with a secret the store never
+// sees, so that passwords cannot be recovered from it, and the response,
+// which never contains them.
+type IdempotencyStore interface {
+	// Reserve atomically claims key for a request with the given
+	// fingerprint. If key is already claimed it returns the existing record
+	// and false instead.
+	Reserve(ctx context.Context, key, fingerprint string) (*IdempotencyRecord, bool, error)
+	// Complete stores the response of the request that reserved key
+	Complete(ctx context.Context, key string, response []byte) error
+	// Release forgets key after its request failed, so that it can be retried
+	Release(ctx context.Context, key string) error
+}
+
+// idempotentRequest is implemented by every mutating request message
+type idempotentRequest interface {
+	proto.Message
+	GetRequestId() string
+}
+
+// idempotent runs handle at most once per request ID of method. Repeated
+// requests get the original response, or ErrRequestInProgress while it is
+// still running; failed requests are forgotten so that they can be retried.
+func idempotent[Resp proto.Message](ctx context.Context, s *UserServiceServer, method string, req idempotentRequest, handle func(context.Context) (Resp, error)) (Resp, error) {
+	var zero Resp
+
+	requestID := req.GetRequestId()
+	if requestID == "" || s.idempotency == nil {
+		return handle(ctx)
+	}
+	if len(requestID) > MaxRequestIDLength {
+		return zero, s.convertError(models.NewValidationError("request_id", fmt.Sprintf("must be at most %d bytes", MaxRequestIDLength)))
+	}
+
+	scope := idempotencyScope(ctx)
+	fingerprint, err := s.requestFingerprint(method, scope, req)
+	if err != nil {
+		return zero, s.convertError(err)
+	}
+
+	key := method + "/" + scope + requestID
+	record, reserved, err := s.idempotency.Reserve(ctx, key, fingerprint)
+	if err != nil {
+		return zero, s.convertError(err)
+	}
+	if !reserved {
+		return replayResponse[Resp](s, record, fingerprint)
+	}
+
+	// The outcome must be recorded even if the client has gone away
+	storeCtx := context.WithoutCancel(ctx)
+
+	resp, err := handle(ctx)
+	if err != nil {
+		_ = s.idempotency.Release(storeCtx, key)
+		return zero, err
+	}
+
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		_ = s.idempotency.Release(storeCtx, key)
+		return resp, nil
+	}
+	if err := s.idempotency.Complete(storeCtx, key, data); err != nil {
+		_ = s.idempotency.Release(storeCtx, key)
+	}
+	return resp, nil
+}
+
+// replayResponse returns the stored response of a repeated request
+func replayResponse[Resp proto.Message](s *UserServiceServer, record *IdempotencyRecord, fingerprint string) (Resp, error) {
+	var zero Resp
+
+	if record.Fingerprint != fingerprint {
+		return zero, s.convertError(models.NewValidationError("request_id", "was already used for a different request"))
+	}
+	if record.Response == nil {
+		return zero, s.convertError(models.ErrRequestInProgress)
+	}
+
+	resp := zero.ProtoReflect().Type().New().Interface().(Resp)
+	if err := proto.Unmarshal(record.Response, resp); err != nil {
+		return zero, s.convertError(err)
+	}
+	return resp, nil
+}
+
+// unfingerprintedFields are left out of request fingerprints: the request
+// ID is the key. Passwords are covered, so that a request ID reused with a
+// different password is rejected rather than replayed.
+var unfingerprintedFields = []protoreflect.Name{"request_id"}
+
+// idempotencyScope identifies the authenticated caller, so that request IDs
+// of different principals never share a record and a replay is never
+// answered to someone who was not authorized for the original request. It
+// is empty for calls without a principal.
+func idempotencyScope(ctx context.Context) string {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return strconv.Quote(principal.Tenant) + "/" + strconv.Quote(principal.ID) + "/"
+}
+
+// requestFingerprint computes an HMAC of method, the caller's scope and req
+// without unfingerprintedFields
+func (s *UserServiceServer) requestFingerprint(method, scope string, req idempotentRequest) (string, error) {
+	msg := proto.Clone(req)
+	reflected := msg.ProtoReflect()
+	fields := reflected.Descriptor().Fields()
+	for _, name := range unfingerprintedFields {
+		if field := fields.ByName(name); field != nil {
+			reflected.Clear(field)
+		}
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, s.fingerprintKey)
+	mac.Write([]byte(method + "\x00" + scope + "\x00"))
+	mac.Write(data)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// newRandomFingerprintKey creates a random per-process fingerprint key
+func newRandomFingerprintKey() []byte {
+	key := make([]byte, MinIdempotencyKeySize)
+	if _, err := rand.Read(key); err != nil {
+		panic("server: failed to generate idempotency key: " + err.Error())
+	}
+	return key
+}
+
+// MemoryIdempotencyStore is an IdempotencyStore for a single replica. Keys
+// expire after a fixed TTL, including those of requests that never
+// completed, and expired keys are swept lazily.
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	records   map[string]*memoryIdempotencyEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryIdempotencyEntry struct {
+	record    IdempotencyRecord
+	expiresAt time.Time
+}
+
+// NewMemoryIdempotencyStore creates an in-memory store. A non-positive ttl
+// uses DefaultIdempotencyTTL.
+func NewMemoryIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+
+	return &MemoryIdempotencyStore{
+		ttl:     ttl,
+		records: make(map[string]*memoryIdempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+// Reserve implements IdempotencyStore
+func (m *MemoryIdempotencyStore) Reserve(ctx context.Context, key, fingerprint string) (*IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if entry, ok := m.records[key]; ok && now.Before(entry.expiresAt) {
+		record := entry.record
+		return &record, false, nil
+	}
+
+	m.records[key] = &memoryIdempotencyEntry{
+		record:    IdempotencyRecord{Fingerprint: fingerprint},
+		expiresAt: now.Add(m.ttl),
+	}
+	return nil, true, nil
+}
+
+// Complete implements IdempotencyStore
+func (m *MemoryIdempotencyStore) Complete(ctx context.Context, key string, response []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.records[key]
+	if !ok {
+		return fmt.Errorf("request %s is not reserved", key)
+	}
+	entry.record.Response = response
+	entry.expiresAt = m.now().Add(m.ttl)
+	return nil
+}
+
+// Release implements IdempotencyStore
+func (m *MemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.records, key)
+	return nil
+}
+
+// sweep drops expired records at most once per TTL; m.mu must be held
+func (m *MemoryIdempotencyStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < m.ttl {
+		return
+	}
+	m.lastSweep = now
+
+	for key, entry := range m.records {
+		if !now.Before(entry.expiresAt) {
+			delete(m.records, key)
+		}
+	}
+}
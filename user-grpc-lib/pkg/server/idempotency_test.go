@@ -0,0 +1,268 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+func newCreateUserRequest(requestID string) *pb.CreateUserRequest {
+	return &pb.CreateUserRequest{
+		Email:     "test@example.com",
+		Password:  "password123",
+		FirstName: "John",
+		LastName:  "Doe",
+		RequestId: requestID,
+	}
+}
+
+func TestUserServiceServer_CreateUser_ReplaysRequestID(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("CreateUser", mock.Anything, mock.Anything).Return(&models.UserModel{
+		ID:    "1",
+		Email: "test@example.com",
+		Role:  models.RoleUser,
+	}, nil).Once()
+
+	server := newTestServer(t, mockService)
+
+	first, err := server.CreateUser(context.Background(), newCreateUserRequest("req-1"))
+	require.NoError(t, err)
+
+	second, err := server.CreateUser(context.Background(), newCreateUserRequest("req-1"))
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(first, second))
+
+	// A different payload under the same request ID is rejected
+	changed := newCreateUserRequest("req-1")
+	changed.FirstName = "Jane"
+	_, err = server.CreateUser(context.Background(), changed)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_CreateUser_WithoutRequestID(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("CreateUser", mock.Anything, mock.Anything).Return(&models.UserModel{ID: "1"}, nil).Twice()
+
+	server := newTestServer(t, mockService)
+
+	for i := 0; i < 2; i++ {
+		_, err := server.CreateUser(context.Background(), newCreateUserRequest(""))
+		require.NoError(t, err)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_CreateUser_FailureReleasesRequestID(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("CreateUser", mock.Anything, mock.Anything).Return(nil, errors.New("connection reset")).Once()
+	mockService.On("CreateUser", mock.Anything, mock.Anything).Return(&models.UserModel{ID: "1"}, nil).Once()
+
+	server := newTestServer(t, mockService)
+
+	_, err := server.CreateUser(context.Background(), newCreateUserRequest("req-1"))
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	resp, err := server.CreateUser(context.Background(), newCreateUserRequest("req-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "1", resp.User.Id)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_CreateUser_RequestInProgress(t *testing.T) {
+	mockService := &MockUserService{}
+	store := NewMemoryIdempotencyStore(time.Minute)
+	server := newTestServer(t, mockService, WithIdempotencyStore(store))
+
+	req := newCreateUserRequest("req-1")
+	fingerprint, err := server.requestFingerprint("CreateUser", "", req)
+	require.NoError(t, err)
+	_, reserved, err := store.Reserve(context.Background(), "CreateUser/req-1", fingerprint)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	_, err = server.CreateUser(context.Background(), req)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Aborted, st.Code())
+	require.NotEmpty(t, st.Details())
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, models.ReasonRequestInProgress, info.Reason)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_CreateUser_RequestIDTooLong(t *testing.T) {
+	mockService := &MockUserService{}
+	server := newTestServer(t, mockService)
+
+	long := make([]byte, MaxRequestIDLength+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+
+	_, err := server.CreateUser(context.Background(), newCreateUserRequest(string(long)))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_IdempotencyDisabled(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("CreateUser", mock.Anything, mock.Anything).Return(&models.UserModel{ID: "1"}, nil).Twice()
+
+	server := newTestServer(t, mockService, WithIdempotencyStore(nil))
+
+	for i := 0; i < 2; i++ {
+		_, err := server.CreateUser(context.Background(), newCreateUserRequest("req-1"))
+		require.NoError(t, err)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_RequestIDsAreScopedByMethod(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("DeleteUser", mock.Anything, "1", "2", models.RoleAdmin).Return(nil).Once()
+	mockService.On("UpdateUser", mock.Anything, "1", mock.Anything).Return(&models.UserModel{ID: "1", FirstName: "Jane"}, nil).Once()
+
+	server := newTestServer(t, mockService)
+
+	firstName := "Jane"
+	_, err := server.UpdateUser(context.Background(), &pb.UpdateUserRequest{Id: "1", FirstName: &firstName, RequestId: "shared"})
+	require.NoError(t, err)
+
+	resp, err := server.DeleteUser(context.Background(), &pb.DeleteUserRequest{
+		Id:        "1",
+		ActorId:   "2",
+		ActorRole: pb.Role_ROLE_ADMIN,
+		RequestId: "shared",
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_RequestIDsAreScopedByPrincipal(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("DeleteUser", mock.Anything, "1", "2", models.RoleAdmin).Return(nil).Once()
+	mockService.On("DeleteUser", mock.Anything, "1", "3", models.RoleUser).Return(models.ErrInsufficientRights).Once()
+
+	server := newTestServer(t, mockService)
+	req := &pb.DeleteUserRequest{Id: "1", RequestId: "shared"}
+
+	admin := ContextWithPrincipal(context.Background(), &Principal{ID: "2", Role: models.RoleAdmin, Tenant: "acme"})
+	resp, err := server.DeleteUser(admin, req)
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+
+	// The same request ID from another principal is not answered with the
+	// admin's stored success
+	user := ContextWithPrincipal(context.Background(), &Principal{ID: "3", Role: models.RoleUser, Tenant: "acme"})
+	_, err = server.DeleteUser(user, req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	mockService.AssertExpectations(t)
+}
+
+// assertDifferentRequest checks that err rejects a request ID reused for a
+// different request
+func assertDifferentRequest(t *testing.T, err error) {
+	t.Helper()
+
+	assertReason(t, err, codes.InvalidArgument, models.ReasonValidationFailed)
+	st, _ := status.FromError(err)
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			require.Len(t, badRequest.FieldViolations, 1)
+			assert.Equal(t, "request_id", badRequest.FieldViolations[0].Field)
+			assert.Equal(t, "was already used for a different request", badRequest.FieldViolations[0].Description)
+			return
+		}
+	}
+	t.Fatal("no BadRequest detail")
+}
+
+func TestUserServiceServer_RequestIDWithDifferentPassword(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("CreateUser", mock.Anything, mock.Anything).Return(&models.UserModel{ID: "123"}, nil).Once()
+	mockService.On("UpdatePassword", mock.Anything, "123", mock.Anything).Return(nil).Once()
+	server := newTestServer(t, mockService)
+
+	_, err := server.CreateUser(context.Background(), newCreateUserRequest("req-1"))
+	require.NoError(t, err)
+	changed := newCreateUserRequest("req-1")
+	changed.Password = "other-password"
+	_, err = server.CreateUser(context.Background(), changed)
+	assertDifferentRequest(t, err)
+
+	update := &pb.UpdatePasswordRequest{Id: "123", CurrentPassword: "old-secret", NewPassword: "new-secret", RequestId: "req-2"}
+	_, err = server.UpdatePassword(context.Background(), update)
+	require.NoError(t, err)
+	update.NewPassword = "newer-secret"
+	_, err = server.UpdatePassword(context.Background(), update)
+	assertDifferentRequest(t, err)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRequestFingerprint_KeyedByServer(t *testing.T) {
+	key := []byte("idempotency-key-at-least-32-bytes")
+	req := newCreateUserRequest("req-1")
+
+	fingerprint, err := newTestServer(t, &MockUserService{}, WithIdempotencyKey(key)).requestFingerprint("CreateUser", "", req)
+	require.NoError(t, err)
+	replica, err := newTestServer(t, &MockUserService{}, WithIdempotencyKey(key)).requestFingerprint("CreateUser", "", req)
+	require.NoError(t, err)
+	assert.Equal(t, fingerprint, replica)
+
+	other, err := newTestServer(t, &MockUserService{}).requestFingerprint("CreateUser", "", req)
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprint, other)
+
+	_, err = NewUserServiceServer(&MockUserService{}, WithIdempotencyKey([]byte("short")))
+	assert.Error(t, err)
+}
+
+func TestMemoryIdempotencyStore_Expiry(t *testing.T) {
+	store := NewMemoryIdempotencyStore(time.Minute)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, reserved, err := store.Reserve(ctx, "k", "fp")
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.NoError(t, store.Complete(ctx, "k", []byte("resp")))
+
+	record, reserved, err := store.Reserve(ctx, "k", "fp")
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, &IdempotencyRecord{Fingerprint: "fp", Response: []byte("resp")}, record)
+
+	now = now.Add(2 * time.Minute)
+	_, reserved, err = store.Reserve(ctx, "k", "other")
+	require.NoError(t, err)
+	assert.True(t, reserved)
+
+	require.NoError(t, store.Release(ctx, "k"))
+	assert.Error(t, store.Complete(ctx, "k", nil))
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// ImportUsers implements the ImportUsers gRPC method. Records are processed
+// in order and each one is answered as soon as it is done, so a failed
+// record never aborts the rest of the import. Only a broken stream or a
+// canceled call ends it early.
+func (s *UserServiceServer) ImportUsers(stream pb.UserService_ImportUsersServer) error {
+	ctx := stream.Context()
+
+	var dryRun bool
+	seen := make(map[string]bool)
+	for index := int64(0); ; index++ {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if index == 0 {
+			dryRun = req.DryRun
+		}
+
+		user, err := s.importUser(ctx, req.User, dryRun, seen)
+		if ctx.Err() != nil {
+			return s.convertError(ctx.Err())
+		}
+
+		resp := &pb.ImportUsersResponse{Index: index}
+		if err != nil {
+			resp.Error = newImportError(s.convertError(err))
+		} else {
+			resp.User = s.converter.ConvertUserToProto(user)
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// importUser validates and, unless dryRun is set, creates a single user.
+// seen holds the lower-cased emails accepted earlier in the same import.
+func (s *UserServiceServer) importUser(ctx context.Context, req *pb.CreateUserRequest, dryRun bool, seen map[string]bool) (*models.UserModel, error) {
+	if err := validateCreateUserRequest(req); err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(req.Email)
+	if seen[email] {
+		return nil, fmt.Errorf("email %s appears earlier in the import: %w", req.Email, models.ErrAlreadyExists)
+	}
+
+	if dryRun {
+		_, err := s.userService.GetUserByEmail(ctx, req.Email)
+		switch status.Code(s.convertError(err)) {
+		case codes.OK:
+			return nil, fmt.Errorf("user with email %s: %w", req.Email, models.ErrAlreadyExists)
+		case codes.NotFound:
+		default:
+			return nil, err
+		}
+		seen[email] = true
+		return nil, nil
+	}
+
+	user, err := s.userService.CreateUser(ctx, models.UserCreateInput{
+		Email:     req.Email,
+		Password:  req.Password,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	seen[email] = true
+
+	return user, nil
+}
+
+// validateCreateUserRequest reports every missing or malformed field of req
+func validateCreateUserRequest(req *pb.CreateUserRequest) error {
+	if req == nil {
+		return models.NewValidationError("user", "must be set")
+	}
+
+	var errs []error
+	for _, field := range []struct{ name, value string }{
+		{"email", req.Email},
+		{"password", req.Password},
+		{"first_name", req.FirstName},
+		{"last_name", req.LastName},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			errs = append(errs, models.NewValidationError(field.name, "must not be empty"))
+		}
+	}
+	if req.Email != "" {
+		if addr, err := mail.ParseAddress(req.Email); err != nil || addr.Address != req.Email {
+			errs = append(errs, models.NewValidationError("email", "must be a valid email address"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// newImportError describes a status error produced by convertError
+func newImportError(err error) *pb.ImportError {
+	st := status.Convert(err)
+
+	result := &pb.ImportError{
+		Code:    int32(st.Code()),
+		Message: st.Message(),
+	}
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			result.Reason = d.Reason
+		case *errdetails.BadRequest:
+			for _, v := range d.FieldViolations {
+				result.FieldViolations = append(result.FieldViolations, &pb.FieldViolation{
+					Field:       v.Field,
+					Description: v.Description,
+				})
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// fakeImportStream replays requests and collects the responses of ImportUsers
+type fakeImportStream struct {
+	grpc.ServerStream
+	requests  []*pb.ImportUsersRequest
+	responses []*pb.ImportUsersResponse
+}
+
+func (f *fakeImportStream) Context() context.Context {
+	return context.Background()
+}
+
+func (f *fakeImportStream) Recv() (*pb.ImportUsersRequest, error) {
+	if len(f.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := f.requests[0]
+	f.requests = f.requests[1:]
+	return req, nil
+}
+
+func (f *fakeImportStream) Send(resp *pb.ImportUsersResponse) error {
+	f.responses = append(f.responses, resp)
+	return nil
+}
+
+func importRequest(email string) *pb.ImportUsersRequest {
+	return &pb.ImportUsersRequest{User: &pb.CreateUserRequest{
+		Email:     email,
+		Password:  "password123",
+		FirstName: "John",
+		LastName:  "Doe",
+	}}
+}
+
+func TestUserServiceServer_ImportUsers(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("CreateUser", mock.Anything, mock.MatchedBy(func(input models.UserCreateInput) bool {
+		return input.Email == "john@example.com"
+	})).Return(&models.UserModel{ID: "1", Email: "john@example.com"}, nil)
+	mockService.On("CreateUser", mock.Anything, mock.MatchedBy(func(input models.UserCreateInput) bool {
+		return input.Email == "taken@example.com"
+	})).Return(nil, models.ErrAlreadyExists)
+
+	server := newTestServer(t, mockService)
+	stream := &fakeImportStream{requests: []*pb.ImportUsersRequest{
+		importRequest("john@example.com"),
+		{User: &pb.CreateUserRequest{Email: "not-an-email"}},
+		importRequest("taken@example.com"),
+		importRequest("JOHN@example.com"),
+	}}
+
+	require.NoError(t, server.ImportUsers(stream))
+	require.Len(t, stream.responses, 4)
+
+	assert.Equal(t, "1", stream.responses[0].User.Id)
+	assert.Nil(t, stream.responses[0].Error)
+
+	invalid := stream.responses[1]
+	assert.Equal(t, int64(1), invalid.Index)
+	assert.Equal(t, int32(codes.InvalidArgument), invalid.Error.Code)
+	assert.Equal(t, models.ReasonValidationFailed, invalid.Error.Reason)
+	var fields []string
+	for _, v := range invalid.Error.FieldViolations {
+		fields = append(fields, v.Field)
+	}
+	assert.Equal(t, []string{"password", "first_name", "last_name", "email"}, fields)
+
+	assert.Equal(t, int32(codes.AlreadyExists), stream.responses[2].Error.Code)
+	assert.Equal(t, models.ReasonAlreadyExists, stream.responses[2].Error.Reason)
+
+	// Duplicates within the import are caught before reaching the adapter
+	assert.Equal(t, int32(codes.AlreadyExists), stream.responses[3].Error.Code)
+	mockService.AssertNumberOfCalls(t, "CreateUser", 2)
+}
+
+func TestUserServiceServer_ImportUsers_DryRun(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("GetUserByEmail", mock.Anything, "new@example.com").Return(nil, models.ErrNotFound)
+	mockService.On("GetUserByEmail", mock.Anything, "taken@example.com").Return(&models.UserModel{ID: "1"}, nil)
+
+	server := newTestServer(t, mockService)
+	first := importRequest("new@example.com")
+	first.DryRun = true
+	stream := &fakeImportStream{requests: []*pb.ImportUsersRequest{
+		first,
+		importRequest("taken@example.com"),
+		importRequest("new@example.com"),
+	}}
+
+	require.NoError(t, server.ImportUsers(stream))
+	require.Len(t, stream.responses, 3)
+
+	assert.Nil(t, stream.responses[0].Error)
+	assert.Nil(t, stream.responses[0].User)
+	assert.Equal(t, int32(codes.AlreadyExists), stream.responses[1].Error.Code)
+	assert.Equal(t, int32(codes.AlreadyExists), stream.responses[2].Error.Code)
+	mockService.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
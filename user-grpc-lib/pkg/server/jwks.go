@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/token"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+const (
+	// JWKSPath is where NewJWKSHandler is conventionally mounted
+	JWKSPath = "/.well-known/jwks.json"
+	// DefaultJWKSMaxAge is how long HTTP clients may cache the key set
+	DefaultJWKSMaxAge = 5 * time.Minute
+)
+
+// GetSigningKeys implements the GetSigningKeys gRPC method. It publishes
+// the public keys of the key set given with WithSigningKeys: the active key
+// and those rotated out but not yet removed.
+func (s *UserServiceServer) GetSigningKeys(ctx context.Context, req *pb.GetSigningKeysRequest) (*pb.GetSigningKeysResponse, error) {
+	if s.signingKeys == nil {
+		return nil, status.Error(codes.Unimplemented, "signing keys are not published by this server")
+	}
+
+	jwks := s.signingKeys.PublicJWKS()
+	resp := &pb.GetSigningKeysResponse{Keys: make([]*pb.JsonWebKey, 0, len(jwks.Keys))}
+	for _, jwk := range jwks.Keys {
+		resp.Keys = append(resp.Keys, s.converter.ConvertJWKToProto(jwk))
+	}
+	return resp, nil
+}
+
+// ConvertJWKToProto converts a public JWK to its protobuf message
+func (c *ModelConverter) ConvertJWKToProto(jwk token.JWK) *pb.JsonWebKey {
+	return &pb.JsonWebKey{
+		Kty: jwk.KeyType,
+		Kid: jwk.KeyID,
+		Alg: jwk.Algorithm,
+		Use: jwk.Use,
+		N:   jwk.N,
+		E:   jwk.E,
+		Crv: jwk.Curve,
+		X:   jwk.X,
+		Y:   jwk.Y,
+	}
+}
+
+// NewJWKSHandler serves the public keys of keys as a JSON Web Key Set, for
+// verifiers that fetch keys over HTTP. Mount it at JWKSPath.
+func NewJWKSHandler(keys *token.KeySet) http.Handler {
+	cacheControl := fmt.Sprintf("public, max-age=%d", int(DefaultJWKSMaxAge.Seconds()))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := json.Marshal(keys.PublicJWKS())
+		if err != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/jwk-set+json")
+		w.Header().Set("Cache-Control", cacheControl)
+		_, _ = w.Write(body)
+	})
+}
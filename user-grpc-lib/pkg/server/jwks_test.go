@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/token"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+func newRotatedKeySet(t *testing.T) *token.KeySet {
+	t.Helper()
+
+	previous, err := token.GenerateKey("2024-01", token.RS256)
+	require.NoError(t, err)
+	active, err := token.GenerateKey("2024-02", token.EdDSA)
+	require.NoError(t, err)
+	keys, err := token.NewKeySet(active, previous)
+	require.NoError(t, err)
+	return keys
+}
+
+func TestUserServiceServer_GetSigningKeys(t *testing.T) {
+	server := newTestServer(t, &MockUserService{})
+	_, err := server.GetSigningKeys(context.Background(), &pb.GetSigningKeysRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	server = newTestServer(t, &MockUserService{}, WithSigningKeys(newRotatedKeySet(t)))
+	resp, err := server.GetSigningKeys(context.Background(), &pb.GetSigningKeysRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Keys, 2)
+
+	assert.Equal(t, "2024-01", resp.Keys[0].Kid)
+	assert.Equal(t, "RSA", resp.Keys[0].Kty)
+	assert.NotEmpty(t, resp.Keys[0].N)
+	assert.Equal(t, "2024-02", resp.Keys[1].Kid)
+	assert.Equal(t, "OKP", resp.Keys[1].Kty)
+	assert.Equal(t, "Ed25519", resp.Keys[1].Crv)
+}
+
+func TestJWKSHandler(t *testing.T) {
+	handler := NewJWKSHandler(newRotatedKeySet(t))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, JWKSPath, nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/jwk-set+json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=300", rec.Header().Get("Cache-Control"))
+
+	var jwks token.JWKS
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jwks))
+	require.Len(t, jwks.Keys, 2)
+	for _, jwk := range jwks.Keys {
+		_, err := token.ParseJWK(jwk)
+		assert.NoError(t, err)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, JWKSPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
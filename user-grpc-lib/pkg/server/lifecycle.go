@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// Minimum actor roles for the soft-delete lifecycle methods
+const (
+	RestoreUserMinRole = models.RoleModerator
+	PurgeUserMinRole   = models.RoleAdmin
+)
+
+// roleRanks orders roles by privilege
+var roleRanks = map[models.Role]int{
+	models.RoleUser:      1,
+	models.RoleModerator: 2,
+	models.RoleAdmin:     3,
+}
+
+// Restorer is an optional extension of UserServiceInterface for adapters
+// whose DeleteUser only soft-deletes users. RestoreUser clears DeletedAt and
+// returns the restored user; it should return models.ErrNotDeleted for users
+// that are not deleted. Without it RestoreUser is unimplemented.
+type Restorer interface {
+	RestoreUser(ctx context.Context, id string, actorID string, actorRole models.Role) (*models.UserModel, error)
+}
+
+// Purger is an optional extension of UserServiceInterface for adapters that
+// can permanently remove soft-deleted users. PurgeUser should return
+// models.ErrNotDeleted for users that were not soft-deleted first. Without
+// it PurgeUser is unimplemented.
+type Purger interface {
+	PurgeUser(ctx context.Context, id string, actorID string, actorRole models.Role) error
+}
+
+// RestoreUser implements the RestoreUser gRPC method.
+// Requests with a request_id are applied at most once.
+func (s *UserServiceServer) RestoreUser(ctx context.Context, req *pb.RestoreUserRequest) (*pb.RestoreUserResponse, error) {
+	return idempotent(ctx, s, "RestoreUser", req, func(ctx context.Context) (*pb.RestoreUserResponse, error) {
+		return s.restoreUser(ctx, req)
+	})
+}
+
+func (s *UserServiceServer) restoreUser(ctx context.Context, req *pb.RestoreUserRequest) (*pb.RestoreUserResponse, error) {
+	restorer, ok := s.userService.(Restorer)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "restoring users is not supported by this server")
+	}
+
+	actorID, actorRole, err := s.checkActor(ctx, req.Id, req.ActorId, req.ActorRole, RestoreUserMinRole)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+
+	user, err := restorer.RestoreUser(ctx, req.Id, actorID, actorRole)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+
+	return &pb.RestoreUserResponse{
+		User: s.converter.ConvertUserToProto(user),
+	}, nil
+}
+
+// PurgeUser implements the PurgeUser gRPC method.
+// Requests with a request_id are applied at most once.
+func (s *UserServiceServer) PurgeUser(ctx context.Context, req *pb.PurgeUserRequest) (*pb.PurgeUserResponse, error) {
+	return idempotent(ctx, s, "PurgeUser", req, func(ctx context.Context) (*pb.PurgeUserResponse, error) {
+		return s.purgeUser(ctx, req)
+	})
+}
+
+func (s *UserServiceServer) purgeUser(ctx context.Context, req *pb.PurgeUserRequest) (*pb.PurgeUserResponse, error) {
+	purger, ok := s.userService.(Purger)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "purging users is not supported by this server")
+	}
+
+	actorID, actorRole, err := s.checkActor(ctx, req.Id, req.ActorId, req.ActorRole, PurgeUserMinRole)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+
+	if err := purger.PurgeUser(ctx, req.Id, actorID, actorRole); err != nil {
+		return nil, s.convertError(err)
+	}
+
+	return &pb.PurgeUserResponse{
+		Success: true,
+	}, nil
+}
+
+// checkActor validates the target and actor of a lifecycle request and
+// rejects actors below minRole
+func (s *UserServiceServer) checkActor(ctx context.Context, id, actorID string, actorRole pb.Role, minRole models.Role) (string, models.Role, error) {
+	if id == "" {
+		return "", "", models.NewValidationError("id", "must not be empty")
+	}
+
+	actorID, actorRole, err := s.resolveActor(ctx, actorID, actorRole)
+	if err != nil {
+		return "", "", err
+	}
+	if actorID == "" {
+		return "", "", models.NewValidationError("actor_id", "must not be empty")
+	}
+	if !isSpecifiedRole(actorRole) {
+		return "", "", models.NewValidationError("actor_role", "must be a known role")
+	}
+
+	role := s.converter.ConvertRoleFromProto(actorRole)
+	if roleRanks[role] < roleRanks[minRole] {
+		return "", "", fmt.Errorf("role %s is required: %w", minRole, models.ErrInsufficientRights)
+	}
+	return actorID, role, nil
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// MockLifecycleUserService additionally implements Restorer and Purger
+type MockLifecycleUserService struct {
+	MockUserService
+}
+
+func (m *MockLifecycleUserService) RestoreUser(ctx context.Context, id string, actorID string, actorRole models.Role) (*models.UserModel, error) {
+	args := m.Called(ctx, id, actorID, actorRole)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserModel), args.Error(1)
+}
+
+func (m *MockLifecycleUserService) PurgeUser(ctx context.Context, id string, actorID string, actorRole models.Role) error {
+	args := m.Called(ctx, id, actorID, actorRole)
+	return args.Error(0)
+}
+
+func TestUserServiceServer_RestoreUser(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.RestoreUserRequest
+		mockSetup     func(*MockLifecycleUserService)
+		expectedError codes.Code
+	}{
+		{
+			name:    "moderator restores user",
+			request: &pb.RestoreUserRequest{Id: "123", ActorId: "admin-1", ActorRole: pb.Role_ROLE_MODERATOR},
+			mockSetup: func(m *MockLifecycleUserService) {
+				m.On("RestoreUser", mock.Anything, "123", "admin-1", models.RoleModerator).Return(&models.UserModel{ID: "123"}, nil)
+			},
+		},
+		{
+			name:          "user may not restore",
+			request:       &pb.RestoreUserRequest{Id: "123", ActorId: "user-1", ActorRole: pb.Role_ROLE_USER},
+			mockSetup:     func(m *MockLifecycleUserService) {},
+			expectedError: codes.PermissionDenied,
+		},
+		{
+			name:          "missing actor role",
+			request:       &pb.RestoreUserRequest{Id: "123", ActorId: "admin-1"},
+			mockSetup:     func(m *MockLifecycleUserService) {},
+			expectedError: codes.InvalidArgument,
+		},
+		{
+			name:    "user is not deleted",
+			request: &pb.RestoreUserRequest{Id: "123", ActorId: "admin-1", ActorRole: pb.Role_ROLE_ADMIN},
+			mockSetup: func(m *MockLifecycleUserService) {
+				m.On("RestoreUser", mock.Anything, "123", "admin-1", models.RoleAdmin).Return(nil, models.ErrNotDeleted)
+			},
+			expectedError: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockLifecycleUserService{}
+			tt.mockSetup(mockService)
+
+			server := newTestServer(t, mockService)
+
+			resp, err := server.RestoreUser(context.Background(), tt.request)
+
+			assert.Equal(t, tt.expectedError, status.Code(err))
+			if tt.expectedError == codes.OK {
+				assert.Equal(t, tt.request.Id, resp.User.Id)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServiceServer_PurgeUser(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.PurgeUserRequest
+		mockSetup     func(*MockLifecycleUserService)
+		expectedError codes.Code
+	}{
+		{
+			name:    "admin purges user",
+			request: &pb.PurgeUserRequest{Id: "123", ActorId: "admin-1", ActorRole: pb.Role_ROLE_ADMIN},
+			mockSetup: func(m *MockLifecycleUserService) {
+				m.On("PurgeUser", mock.Anything, "123", "admin-1", models.RoleAdmin).Return(nil)
+			},
+		},
+		{
+			name:          "moderator may not purge",
+			request:       &pb.PurgeUserRequest{Id: "123", ActorId: "mod-1", ActorRole: pb.Role_ROLE_MODERATOR},
+			mockSetup:     func(m *MockLifecycleUserService) {},
+			expectedError: codes.PermissionDenied,
+		},
+		{
+			name:          "missing id",
+			request:       &pb.PurgeUserRequest{ActorId: "admin-1", ActorRole: pb.Role_ROLE_ADMIN},
+			mockSetup:     func(m *MockLifecycleUserService) {},
+			expectedError: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockLifecycleUserService{}
+			tt.mockSetup(mockService)
+
+			server := newTestServer(t, mockService)
+
+			resp, err := server.PurgeUser(context.Background(), tt.request)
+
+			assert.Equal(t, tt.expectedError, status.Code(err))
+			if tt.expectedError == codes.OK {
+				assert.True(t, resp.Success)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServiceServer_LifecycleUnimplemented(t *testing.T) {
+	server := newTestServer(t, &MockUserService{})
+
+	_, err := server.RestoreUser(context.Background(), &pb.RestoreUserRequest{Id: "1", ActorId: "2", ActorRole: pb.Role_ROLE_ADMIN})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, err = server.PurgeUser(context.Background(), &pb.PurgeUserRequest{Id: "1", ActorId: "2", ActorRole: pb.Role_ROLE_ADMIN})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
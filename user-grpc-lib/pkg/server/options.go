@@ -0,0 +1,162 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/token"
+)
+
+// Option configures a UserServiceServer
+type Option func(*UserServiceServer)
+
+// WithPageTokenKey sets the HMAC key used to sign GetUsers page tokens.
+// Without this option a random key is generated and tokens do not survive a
+// restart. Keys shorter than MinPageTokenKeySize would make tokens
+// forgeable, so NewUserServiceServer rejects them.
+func WithPageTokenKey(key []byte) Option {
+	return func(s *UserServiceServer) {
+		if len(key) < MinPageTokenKeySize {
+			s.optionErrs = append(s.optionErrs, fmt.Errorf("page token key must be at least %d bytes, got %d", MinPageTokenKeySize, len(key)))
+			return
+		}
+		s.pageTokens = newPageTokenCodec(key)
+	}
+}
+
+// WithMaxPageSize sets the largest page size GetUsers will return; larger
+// requests are coerced down to it
+func WithMaxPageSize(maxPageSize int64) Option {
+	return func(s *UserServiceServer) {
+		if maxPageSize > 0 {
+			s.maxPageSize = maxPageSize
+		}
+	}
+}
+
+// WithSearchScanLimit sets how many users SearchUsers examines for adapters
+// that do not implement Searcher
+func WithSearchScanLimit(limit int64) Option {
+	return func(s *UserServiceServer) {
+		if limit > 0 {
+			s.searchScanLimit = limit
+		}
+	}
+}
+
+// WithMaxBatchSize sets the largest number of distinct IDs BatchGetUsers accepts
+func WithMaxBatchSize(maxBatchSize int) Option {
+	return func(s *UserServiceServer) {
+		if maxBatchSize > 0 {
+			s.maxBatchSize = maxBatchSize
+		}
+	}
+}
+
+// WithBatchParallelism bounds the concurrent GetUserByID calls BatchGetUsers
+// makes for adapters that do not implement BatchGetter
+func WithBatchParallelism(parallelism int) Option {
+	return func(s *UserServiceServer) {
+		if parallelism > 0 {
+			s.batchParallelism = parallelism
+		}
+	}
+}
+
+// WithEventSource sets the source of the WatchUsers change feed, typically
+// a Broadcaster the adapter publishes its changes to. It takes precedence
+// over an adapter that implements UserEventSource itself.
+func WithEventSource(source UserEventSource) Option {
+	return func(s *UserServiceServer) {
+		s.eventSource = source
+	}
+}
+
+// WithUpdatableUserPaths sets the fields UpdateUser may change, as field
+// mask paths. Only list fields the adapter's UpdateUser applies: email and
+// rating are passed in UserUpdateInput but ignored by adapters written for
+// DefaultUpdatableUserPaths. Paths UserUpdateInput cannot carry are dropped.
+func WithUpdatableUserPaths(paths ...string) Option {
+	return func(s *UserServiceServer) {
+		s.updatableUserPaths = nil
+		for _, path := range paths {
+			if settableUserPaths[path] {
+				s.updatableUserPaths = append(s.updatableUserPaths, path)
+			}
+		}
+	}
+}
+
+// WithIdempotencyStore sets where the outcome of requests carrying a
+// request_id is remembered. The default is an in-memory store with
+// DefaultIdempotencyTTL; nil disables deduplication.
+func WithIdempotencyStore(store IdempotencyStore) Option {
+	return func(s *UserServiceServer) {
+		s.idempotency = store
+	}
+}
+
+// WithIdempotencyKey sets the HMAC key request fingerprints are computed
+// with. Without this option a random key is generated. Keys shorter than
+// MinIdempotencyKeySize are rejected by NewUserServiceServer.
+func WithIdempotencyKey(key []byte) Option {
+	return func(s *UserServiceServer) {
+		if len(key) < MinIdempotencyKeySize {
+			s.optionErrs = append(s.optionErrs, fmt.Errorf("idempotency key must be at least %d bytes, got %d", MinIdempotencyKeySize, len(key)))
+			return
+		}
+		s.fingerprintKey = key
+	}
+}
+
+// WithSigningKeys publishes the public keys of keys through GetSigningKeys,
+// typically the key set of the token.Issuer the adapter signs tokens with
+func WithSigningKeys(keys *token.KeySet) Option {
+	return func(s *UserServiceServer) {
+		s.signingKeys = keys
+	}
+}
+
+// WithTokenVerifier sets how the server verifies access tokens, for
+// IntrospectToken, RevokeToken and the server's own VerifyToken
+func WithTokenVerifier(verifier *token.Verifier) Option {
+	return func(s *UserServiceServer) {
+		s.tokenVerifier = verifier
+	}
+}
+
+// WithRevocationStore sets where revoked tokens are remembered. The default
+// is an in-memory store; nil disables revocation.
+func WithRevocationStore(store RevocationStore) Option {
+	return func(s *UserServiceServer) {
+		s.revocations = store
+	}
+}
+
+// WithTokenIssuer sets how the server signs the access tokens RefreshToken
+// returns, typically the issuer the adapter's Login uses. Refresh tokens
+// are only issued with it.
+func WithTokenIssuer(issuer *token.Issuer) Option {
+	return func(s *UserServiceServer) {
+		s.tokenIssuer = issuer
+	}
+}
+
+// WithSessionStore sets where sessions and their refresh tokens are kept.
+// The default is an in-memory store; nil disables sessions and refresh
+// tokens.
+func WithSessionStore(store SessionStore) Option {
+	return func(s *UserServiceServer) {
+		s.sessions = store
+	}
+}
+
+// WithRefreshTokenTTL sets how long refresh tokens stay valid. Each refresh
+// extends the session by the same time.
+func WithRefreshTokenTTL(ttl time.Duration) Option {
+	return func(s *UserServiceServer) {
+		if ttl > 0 {
+			s.refreshTokenTTL = ttl
+		}
+	}
+}
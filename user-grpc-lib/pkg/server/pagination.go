@@ -0,0 +1,199 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+const (
+	// DefaultPageSize is used when GetUsers is called without a page size
+	DefaultPageSize int64 = 10
+	// DefaultMaxPageSize is the largest page size GetUsers returns unless
+	// overridden with WithMaxPageSize
+	DefaultMaxPageSize int64 = 100
+)
+
+// CursorLister is an optional extension of UserServiceInterface for adapters
+// that support keyset pagination. The cursor is opaque to the server: an
+// empty cursor requests the first page and the adapter returns the cursor
+// of the following page, or an empty string after the last one. The server
+// signs cursors before handing them to clients, so adapters do not need to
+// protect them against tampering.
+type CursorLister interface {
+	ListUsersCursor(ctx context.Context, query models.UserQuery, cursor string, pageSize int64) (*models.UserCursorPageModel, error)
+}
+
+// FilteredLister is an optional extension of UserServiceInterface for
+// adapters that support filtering and ordering with offset pagination.
+// Without it, GetUsers requests with a filter or order_by are rejected
+// unless the adapter implements CursorLister.
+type FilteredLister interface {
+	ListUsersFiltered(ctx context.Context, query models.UserQuery, page, pageSize int64) (*models.PaginatedUsersModel, error)
+}
+
+var errInvalidPageToken = errors.New("invalid page token")
+
+// pageToken is the signed payload behind GetUsers page tokens. Cursor is
+// set for adapters implementing CursorLister; Page is used to emulate
+// cursors on top of offset pagination for adapters that do not.
+// Query fingerprints the filter and ordering the token was issued for, and
+// Skip counts the users of the page that were already streamed by ExportUsers.
+type pageToken struct {
+	Cursor string `json:"c,omitempty"`
+	Page   int64  `json:"p,omitempty"`
+	Skip   int64  `json:"s,omitempty"`
+	Query  string `json:"q,omitempty"`
+}
+
+// listUsersRequest carries the normalized parameters of a GetUsers call
+type listUsersRequest struct {
+	query       models.UserQuery
+	fingerprint string
+	pageSize    int64
+}
+
+// listUsersPage loads one offset page from ListUsersFiltered, or from
+// ListUsers when the adapter does not support filtering and the request
+// neither filters nor orders users
+func (s *UserServiceServer) listUsersPage(ctx context.Context, list *listUsersRequest, page int64) (*models.PaginatedUsersModel, error) {
+	if filtered, ok := s.userService.(FilteredLister); ok {
+		return filtered.ListUsersFiltered(ctx, list.query, page, list.pageSize)
+	}
+	if list.query.IsZero() {
+		return s.userService.ListUsers(ctx, page, list.pageSize)
+	}
+	return nil, models.NewValidationError("filter", "filtering and ordering are not supported by this server")
+}
+
+// listUsersByOffset serves GetUsers from listUsersPage. Like ExportUsers it
+// drops soft-deleted users the adapter returned despite the filter, so a
+// page may hold fewer users than page_size.
+func (s *UserServiceServer) listUsersByOffset(ctx context.Context, list *listUsersRequest, page int64) (*pb.GetUsersResponse, error) {
+	result, err := s.listUsersPage(ctx, list, page)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+
+	var nextPageToken string
+	if page < result.TotalPages {
+		nextPageToken, err = s.pageTokens.encode(pageToken{Page: page + 1, Query: list.fingerprint})
+		if err != nil {
+			return nil, s.convertError(err)
+		}
+	}
+
+	return &pb.GetUsersResponse{
+		Users:         s.convertUsersToProto(filterDeleted(list.query.Filter, result.Users)),
+		Total:         result.Total,
+		Page:          result.Page,
+		PageSize:      result.PageSize,
+		TotalPages:    result.TotalPages,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// listUsersByCursor serves GetUsers from CursorLister.ListUsersCursor,
+// dropping soft-deleted users like listUsersByOffset
+func (s *UserServiceServer) listUsersByCursor(ctx context.Context, lister CursorLister, list *listUsersRequest, cursor string) (*pb.GetUsersResponse, error) {
+	result, err := lister.ListUsersCursor(ctx, list.query, cursor, list.pageSize)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+
+	var nextPageToken string
+	if result.NextCursor != "" {
+		nextPageToken, err = s.pageTokens.encode(pageToken{Cursor: result.NextCursor, Query: list.fingerprint})
+		if err != nil {
+			return nil, s.convertError(err)
+		}
+	}
+
+	return &pb.GetUsersResponse{
+		Users:         s.convertUsersToProto(filterDeleted(list.query.Filter, result.Users)),
+		Total:         result.Total,
+		PageSize:      list.pageSize,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// MinPageTokenKeySize is the shortest key WithPageTokenKey accepts, in bytes
+const MinPageTokenKeySize = 32
+
+// filterDeleted returns the users that match the soft-delete switches of
+// filter
+func filterDeleted(filter models.UserFilter, users []*models.UserModel) []*models.UserModel {
+	kept := make([]*models.UserModel, 0, len(users))
+	for _, user := range users {
+		if user != nil && matchesDeletedFilter(filter, user) {
+			kept = append(kept, user)
+		}
+	}
+	return kept
+}
+
+// pageTokenCodec encodes and verifies HMAC-signed page tokens
+type pageTokenCodec struct {
+	key []byte
+}
+
+func newPageTokenCodec(key []byte) *pageTokenCodec {
+	return &pageTokenCodec{key: key}
+}
+
+// newRandomPageTokenCodec creates a codec with a random per-process key
+func newRandomPageTokenCodec() *pageTokenCodec {
+	key := make([]byte, MinPageTokenKeySize)
+	if _, err := rand.Read(key); err != nil {
+		panic("server: failed to generate page token key: " + err.Error())
+	}
+	return newPageTokenCodec(key)
+}
+
+func (c *pageTokenCodec) encode(token pageToken) (string, error) {
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(c.sign(payload)), nil
+}
+
+func (c *pageTokenCodec) decode(encoded string) (pageToken, error) {
+	var token pageToken
+
+	encodedPayload, encodedSig, ok := strings.Cut(encoded, ".")
+	if !ok {
+		return token, errInvalidPageToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return token, errInvalidPageToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return token, errInvalidPageToken
+	}
+	if !hmac.Equal(sig, c.sign(payload)) {
+		return token, errInvalidPageToken
+	}
+	if err := json.Unmarshal(payload, &token); err != nil {
+		return token, errInvalidPageToken
+	}
+
+	return token, nil
+}
+
+func (c *pageTokenCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// ConvertUserFilterFromProto converts a protobuf filter to the domain filter.
+// Roles are converted with ConvertRoleFromProto, so callers must reject
+// ROLE_UNSPECIFIED beforehand.
+func (c *ModelConverter) ConvertUserFilterFromProto(filter *pb.UserFilter) models.UserFilter {
+	if filter == nil {
+		return models.UserFilter{}
+	}
+
+	result := models.UserFilter{
+		CreatedAfter:   filter.CreatedAfter,
+		CreatedBefore:  filter.CreatedBefore,
+		UpdatedAfter:   filter.UpdatedAfter,
+		UpdatedBefore:  filter.UpdatedBefore,
+		MinRating:      filter.MinRating,
+		MaxRating:      filter.MaxRating,
+		EmailDomain:    filter.EmailDomain,
+		IncludeDeleted: filter.IncludeDeleted || filter.OnlyDeleted,
+		OnlyDeleted:    filter.OnlyDeleted,
+	}
+	for _, role := range filter.Roles {
+		result.Roles = append(result.Roles, c.ConvertRoleFromProto(role))
+	}
+
+	return result
+}
+
+// parseUserQuery validates and converts the filter and order_by of a list request
+func (s *UserServiceServer) parseUserQuery(filter *pb.UserFilter, orderBy string) (models.UserQuery, error) {
+	var errs []error
+
+	for _, role := range filter.GetRoles() {
+		if !isSpecifiedRole(role) {
+			errs = append(errs, models.NewValidationError("filter.roles", "must not contain ROLE_UNSPECIFIED or unknown roles"))
+			break
+		}
+	}
+
+	query := models.UserQuery{
+		Filter: s.converter.ConvertUserFilterFromProto(filter),
+	}
+	if len(errs) == 0 {
+		if err := query.Filter.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	keys, err := models.ParseOrderBy(orderBy)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	query.OrderBy = keys
+
+	return query, errors.Join(errs...)
+}
+
+// fingerprint identifies the parameters of a paginated request so that page
+// tokens cannot be replayed against a request with different parameters.
+// Empty parameters have an empty fingerprint.
+func fingerprint(params any, empty bool) string {
+	if empty {
+		return ""
+	}
+
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(encoded)
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
+}
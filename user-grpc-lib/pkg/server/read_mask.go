@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// readMaskRequiredPaths are always requested from adapters, because the
+// server needs them to enforce soft deletion
+var readMaskRequiredPaths = []string{"id", "deleted_at"}
+
+// readMask is a validated read mask; the zero value keeps every field
+type readMask struct {
+	fields map[protoreflect.Name]bool
+	paths  []string
+}
+
+// parseReadMask validates the paths of mask against the User message
+func parseReadMask(mask *fieldmaskpb.FieldMask) (readMask, error) {
+	if len(mask.GetPaths()) == 0 {
+		return readMask{}, nil
+	}
+
+	userFields := (&pb.User{}).ProtoReflect().Descriptor().Fields()
+	result := readMask{fields: make(map[protoreflect.Name]bool)}
+	for _, path := range mask.GetPaths() {
+		name := protoreflect.Name(path)
+		if userFields.ByName(name) == nil {
+			return readMask{}, models.NewValidationError("read_mask", fmt.Sprintf("unknown path %q", path))
+		}
+		if !result.fields[name] {
+			result.fields[name] = true
+			result.paths = append(result.paths, path)
+		}
+	}
+
+	return result, nil
+}
+
+// hint passes the fields to load on to the adapter through ctx. Computed
+// etags depend on every field, so requesting the etag disables the hint.
+func (m readMask) hint(ctx context.Context) context.Context {
+	if m.fields == nil || m.fields["etag"] {
+		return ctx
+	}
+
+	paths := append([]string(nil), m.paths...)
+	for _, path := range readMaskRequiredPaths {
+		if !m.fields[protoreflect.Name(path)] {
+			paths = append(paths, path)
+		}
+	}
+	return models.ContextWithReadMask(ctx, paths)
+}
+
+// apply clears the fields of user that are not in the mask
+func (m readMask) apply(user *pb.User) {
+	if m.fields == nil || user == nil {
+		return
+	}
+
+	msg := user.ProtoReflect()
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if fd := fields.Get(i); !m.fields[fd.Name()] {
+			msg.Clear(fd)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+func fullUser(id string) *models.UserModel {
+	return &models.UserModel{
+		ID:        id,
+		Email:     "john@example.com",
+		FirstName: "John",
+		LastName:  "Doe",
+		Role:      models.RoleUser,
+		CreatedAt: timestamppb.Now(),
+		UpdatedAt: timestamppb.Now(),
+		Rating:    5,
+	}
+}
+
+// hasReadMask matches contexts carrying exactly the given read mask hint
+func hasReadMask(paths ...string) any {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		hint := models.ReadMaskFromContext(ctx)
+		if len(paths) == 0 {
+			return hint == nil
+		}
+		return assert.ObjectsAreEqual(paths, hint)
+	})
+}
+
+func TestUserServiceServer_GetUserByID_ReadMask(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("GetUserByID", hasReadMask("first_name", "id", "deleted_at"), "123").Return(fullUser("123"), nil)
+
+	server := newTestServer(t, mockService)
+
+	resp, err := server.GetUserByID(context.Background(), &pb.GetUserByIDRequest{
+		Id:       "123",
+		ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"first_name", "first_name"}},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&pb.User{FirstName: "John"}, resp.User), "got %v", resp.User)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_GetUserByEmail_ReadMaskWithETag(t *testing.T) {
+	user := fullUser("123")
+
+	mockService := &MockUserService{}
+	mockService.On("GetUserByEmail", hasReadMask(), "john@example.com").Return(user, nil)
+
+	server := newTestServer(t, mockService)
+
+	resp, err := server.GetUserByEmail(context.Background(), &pb.GetUserByEmailRequest{
+		Email:    "john@example.com",
+		ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "etag"}},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&pb.User{Id: "123", Etag: user.CurrentETag()}, resp.User), "got %v", resp.User)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_GetUsers_ReadMask(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("ListUsers", hasReadMask("id", "email", "deleted_at"), int64(1), int64(10)).Return(&models.PaginatedUsersModel{
+		Users:      []*models.UserModel{fullUser("1"), fullUser("2")},
+		TotalPages: 1,
+	}, nil)
+
+	server := newTestServer(t, mockService)
+
+	resp, err := server.GetUsers(context.Background(), &pb.GetUsersRequest{
+		ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "email"}},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Users, 2)
+	for _, user := range resp.Users {
+		assert.NotEmpty(t, user.Id)
+		assert.Equal(t, "john@example.com", user.Email)
+		assert.Empty(t, user.FirstName)
+		assert.Nil(t, user.CreatedAt)
+	}
+}
+
+func TestUserServiceServer_BatchGetUsers_ReadMask(t *testing.T) {
+	mockService := &MockBatchUserService{}
+	mockService.On("GetUsersByIDs", hasReadMask("last_name", "id", "deleted_at"), []string{"1"}).Return(map[string]*models.UserModel{
+		"1": fullUser("1"),
+	}, nil)
+
+	server := newTestServer(t, mockService)
+
+	resp, err := server.BatchGetUsers(context.Background(), &pb.BatchGetUsersRequest{
+		Ids:      []string{"1"},
+		ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"last_name"}},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&pb.User{LastName: "Doe"}, resp.Users["1"]), "got %v", resp.Users["1"])
+}
+
+func TestUserServiceServer_ReadMask_UnknownPath(t *testing.T) {
+	server := newTestServer(t, &MockUserService{})
+
+	_, err := server.GetUserByID(context.Background(), &pb.GetUserByIDRequest{
+		Id:       "123",
+		ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"password"}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.GetUsers(context.Background(), &pb.GetUsersRequest{
+		ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"created_at.seconds"}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
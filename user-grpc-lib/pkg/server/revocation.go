@@ -0,0 +1,299 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/token"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// RevokeAllForUserMinRole is the role needed to revoke the tokens of
+// another user; everyone may revoke their own
+const RevokeAllForUserMinRole = models.RoleAdmin
+
+// RevocationStore remembers revoked access tokens until they expire
+type RevocationStore interface {
+	// RevokeToken revokes the token with the given ID ("jti"). The
+	// revocation may be forgotten after expiresAt, when the token expires.
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	// RevokeAllForUser revokes every token issued to userID strictly before
+	// issuedBefore. Token issue times are whole seconds, and the server
+	// passes the start of the current second, so tokens issued within the
+	// second of the call stay valid.
+	RevokeAllForUser(ctx context.Context, userID string, issuedBefore time.Time) error
+	// IsRevoked reports whether a token issued to userID at issuedAt was
+	// revoked
+	IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error)
+}
+
+// MemoryRevocationStore is a RevocationStore for a single replica.
+// Revoked token IDs are swept lazily once they expire; the cut-off times
+// of RevokeAllForUser are kept for the lifetime of the store.
+type MemoryRevocationStore struct {
+	mu        sync.Mutex
+	tokens    map[string]time.Time
+	users     map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryRevocationStore creates an empty in-memory store
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		tokens: make(map[string]time.Time),
+		users:  make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+// RevokeToken implements RevocationStore
+func (m *MemoryRevocationStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep()
+	m.tokens[tokenID] = expiresAt
+	return nil
+}
+
+// RevokeAllForUser implements RevocationStore
+func (m *MemoryRevocationStore) RevokeAllForUser(ctx context.Context, userID string, issuedBefore time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if issuedBefore.After(m.users[userID]) {
+		m.users[userID] = issuedBefore
+	}
+	return nil
+}
+
+// IsRevoked implements RevocationStore
+func (m *MemoryRevocationStore) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.tokens[tokenID]; ok && tokenID != "" {
+		return true, nil
+	}
+	if before, ok := m.users[userID]; ok && issuedAt.Before(before) {
+		return true, nil
+	}
+	return false, nil
+}
+
+// sweep drops revocations of expired tokens at most once a minute; m.mu
+// must be held
+func (m *MemoryRevocationStore) sweep() {
+	now := m.now()
+	if now.Sub(m.lastSweep) < time.Minute {
+		return
+	}
+	m.lastSweep = now
+
+	for tokenID, expiresAt := range m.tokens {
+		if now.After(expiresAt) {
+			delete(m.tokens, tokenID)
+		}
+	}
+}
+
+// VerifyToken implements TokenVerifier with the verifier given to
+// WithTokenVerifier, also rejecting revoked tokens. It is equivalent to
+// NewJWTVerifier with the server's verifier and revocation store.
+func (s *UserServiceServer) VerifyToken(ctx context.Context, raw string) (*Principal, error) {
+	claims, err := s.verifyToken(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+	return principalFromClaims(claims), nil
+}
+
+// verifyToken checks the signature, claims and revocation of a token
+func (s *UserServiceServer) verifyToken(ctx context.Context, raw string) (*token.Claims, error) {
+	if s.tokenVerifier == nil {
+		return nil, errors.New("no token verifier configured")
+	}
+	return verifyJWT(ctx, s.tokenVerifier, s.revocations, raw)
+}
+
+// verifyJWT verifies a token with verifier and rejects it if it was
+// revoked in revocations, unless that is nil
+func verifyJWT(ctx context.Context, verifier *token.Verifier, revocations RevocationStore, raw string) (*token.Claims, error) {
+	claims, err := verifier.Verify(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if revocations != nil {
+		revoked, err := revocations.IsRevoked(ctx, claims.ID, claims.Subject, claims.IssuedAt)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, fmt.Errorf("token revoked: %w", models.ErrInvalidToken)
+		}
+	}
+	return claims, nil
+}
+
+// invalidTokenErrors are the token.Verifier errors of tokens that are not
+// valid, as opposed to failures to check them
+var invalidTokenErrors = []error{
+	token.ErrMalformed,
+	token.ErrUnsupportedAlgorithm,
+	token.ErrUnknownKey,
+	token.ErrInvalidSignature,
+	token.ErrExpired,
+	token.ErrNotYetValid,
+	token.ErrInvalidIssuer,
+	token.ErrInvalidAudience,
+}
+
+// isInvalidToken reports whether err means that the token is not valid
+func isInvalidToken(err error) bool {
+	for _, target := range invalidTokenErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// principalFromClaims returns the principal a token was issued to
+func principalFromClaims(claims *token.Claims) *Principal {
+	return &Principal{
+		ID:     claims.Subject,
+		Role:   models.Role(claims.Role),
+		Tenant: claims.Tenant,
+	}
+}
+
+// IntrospectToken implements the IntrospectToken gRPC method. Tokens that
+// fail verification are reported as inactive rather than as an error.
+func (s *UserServiceServer) IntrospectToken(ctx context.Context, req *pb.IntrospectTokenRequest) (*pb.IntrospectTokenResponse, error) {
+	if s.tokenVerifier == nil {
+		return nil, status.Error(codes.Unimplemented, "token introspection is not supported by this server")
+	}
+	if req.Token == "" {
+		return nil, s.convertError(models.NewValidationError("token", "must not be empty"))
+	}
+
+	claims, err := s.verifyToken(ctx, req.Token)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, s.convertError(err)
+		}
+		return &pb.IntrospectTokenResponse{Active: false}, nil
+	}
+
+	return &pb.IntrospectTokenResponse{
+		Active:    true,
+		Subject:   claims.Subject,
+		Role:      s.converter.ConvertRoleToProto(models.Role(claims.Role)),
+		ExpiresAt: timestamppb.New(claims.ExpiresAt),
+		IssuedAt:  optionalTimestamp(claims.IssuedAt),
+		Scopes:    claims.Scopes,
+		TokenId:   claims.ID,
+		Tenant:    claims.Tenant,
+	}, nil
+}
+
+// RevokeToken implements the RevokeToken gRPC method. Callers may revoke
+// their own tokens, and admins anyone's.
+func (s *UserServiceServer) RevokeToken(ctx context.Context, req *pb.RevokeTokenRequest) (*pb.RevokeTokenResponse, error) {
+	if s.tokenVerifier == nil || s.revocations == nil {
+		return nil, status.Error(codes.Unimplemented, "token revocation is not supported by this server")
+	}
+	if req.Token == "" {
+		return nil, s.convertError(models.NewValidationError("token", "must not be empty"))
+	}
+
+	// As in RFC 7009, tokens that are not valid anyway need no revoking.
+	// Failures to check the token, such as a key lookup that failed or a
+	// canceled call, revoke nothing and are reported.
+	claims, err := s.tokenVerifier.Verify(ctx, req.Token)
+	if err != nil {
+		if isInvalidToken(err) {
+			return &pb.RevokeTokenResponse{Success: true}, nil
+		}
+		return nil, s.convertError(err)
+	}
+	if claims.ID == "" {
+		return nil, s.convertError(models.NewValidationError("token", "has no jti and cannot be revoked individually"))
+	}
+	if err := s.checkSelfOrRole(ctx, claims.Subject, RevokeAllForUserMinRole, "revoke the tokens of others"); err != nil {
+		return nil, s.convertError(err)
+	}
+
+	if err := s.revocations.RevokeToken(ctx, claims.ID, claims.ExpiresAt); err != nil {
+		return nil, s.convertError(err)
+	}
+
+	return &pb.RevokeTokenResponse{
+		Success: true,
+	}, nil
+}
+
+// RevokeAllForUser implements the RevokeAllForUser gRPC method. It revokes
+// every token issued to the user before the current second and ends their
+// sessions. Tokens carry their issue time in whole seconds, so those issued
+// within the second of the revocation, e.g. by an immediate new Login,
+// stay valid.
+func (s *UserServiceServer) RevokeAllForUser(ctx context.Context, req *pb.RevokeAllForUserRequest) (*pb.RevokeAllForUserResponse, error) {
+	if s.revocations == nil && s.sessions == nil {
+		return nil, status.Error(codes.Unimplemented, "token revocation is not supported by this server")
+	}
+	if req.UserId == "" {
+		return nil, s.convertError(models.NewValidationError("user_id", "must not be empty"))
+	}
+	if err := s.checkSelfOrRole(ctx, req.UserId, RevokeAllForUserMinRole, "revoke the tokens of others"); err != nil {
+		return nil, s.convertError(err)
+	}
+
+	if s.revocations != nil {
+		if err := s.revocations.RevokeAllForUser(ctx, req.UserId, time.Now().Truncate(time.Second)); err != nil {
+			return nil, s.convertError(err)
+		}
+	}
+	if s.sessions != nil {
+		if err := s.endSessions(ctx, req.UserId); err != nil {
+			return nil, s.convertError(err)
+		}
+	}
+
+	return &pb.RevokeAllForUserResponse{
+		Success: true,
+	}, nil
+}
+
+// checkSelfOrRole lets authenticated callers act on their own tokens and
+// sessions, and callers with at least minRole on those of anyone.
+// Unauthenticated calls are only possible without AuthInterceptor and are
+// trusted.
+func (s *UserServiceServer) checkSelfOrRole(ctx context.Context, userID string, minRole models.Role, action string) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.ID == userID {
+		return nil
+	}
+	if roleRanks[principal.Role] < roleRanks[minRole] {
+		return fmt.Errorf("only %s users may %s: %w", minRole, action, models.ErrInsufficientRights)
+	}
+	return nil
+}
+
+// optionalTimestamp converts t, leaving zero times unset
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
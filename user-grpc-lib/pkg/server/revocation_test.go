@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/token"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// newTokenServer returns a server verifying tokens signed by keys
+func newTokenServer(t *testing.T) (*UserServiceServer, *token.KeySet) {
+	t.Helper()
+
+	key, err := token.GenerateKey("k1", token.EdDSA)
+	require.NoError(t, err)
+	keys, err := token.NewKeySet(key)
+	require.NoError(t, err)
+
+	server := newTestServer(t, &MockUserService{}, WithTokenVerifier(token.NewVerifier(keys)))
+	return server, keys
+}
+
+func issueToken(t *testing.T, issuer *token.Issuer, claims token.Claims) string {
+	t.Helper()
+
+	raw, _, err := issuer.Issue(claims)
+	require.NoError(t, err)
+	return raw
+}
+
+func TestUserServiceServer_IntrospectToken(t *testing.T) {
+	server, keys := newTokenServer(t)
+	raw, issued, err := token.NewIssuer(keys).Issue(token.Claims{
+		Subject: "123",
+		Role:    string(models.RoleModerator),
+		Tenant:  "acme",
+		Scopes:  []string{"users:read"},
+	})
+	require.NoError(t, err)
+
+	resp, err := server.IntrospectToken(context.Background(), &pb.IntrospectTokenRequest{Token: raw})
+	require.NoError(t, err)
+	assert.True(t, resp.Active)
+	assert.Equal(t, "123", resp.Subject)
+	assert.Equal(t, pb.Role_ROLE_MODERATOR, resp.Role)
+	assert.Equal(t, "acme", resp.Tenant)
+	assert.Equal(t, []string{"users:read"}, resp.Scopes)
+	assert.Equal(t, issued.ID, resp.TokenId)
+	assert.True(t, issued.ExpiresAt.Equal(resp.ExpiresAt.AsTime()))
+
+	resp, err = server.IntrospectToken(context.Background(), &pb.IntrospectTokenRequest{Token: "not-a-token"})
+	require.NoError(t, err)
+	assert.False(t, resp.Active)
+	assert.Empty(t, resp.Subject)
+
+	_, err = server.IntrospectToken(context.Background(), &pb.IntrospectTokenRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestUserServiceServer_RevokeToken(t *testing.T) {
+	server, keys := newTokenServer(t)
+	raw := issueToken(t, token.NewIssuer(keys), token.Claims{Subject: "123", Role: string(models.RoleUser)})
+	interceptor := NewAuthInterceptor(server).Unary()
+	info := &grpc.UnaryServerInfo{FullMethod: pb.UserService_GetUserByID_FullMethodName}
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+
+	_, err := interceptor(incomingContext("Bearer "+raw), nil, info, handler)
+	require.NoError(t, err)
+
+	// Other users may not revoke the token
+	other := ContextWithPrincipal(context.Background(), &Principal{ID: "456", Role: models.RoleModerator})
+	_, err = server.RevokeToken(other, &pb.RevokeTokenRequest{Token: raw})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	owner := ContextWithPrincipal(context.Background(), &Principal{ID: "123", Role: models.RoleUser})
+	resp, err := server.RevokeToken(owner, &pb.RevokeTokenRequest{Token: raw})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+
+	_, err = interceptor(incomingContext("Bearer "+raw), nil, info, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	introspection, err := server.IntrospectToken(context.Background(), &pb.IntrospectTokenRequest{Token: raw})
+	require.NoError(t, err)
+	assert.False(t, introspection.Active)
+
+	// Invalid tokens need no revoking
+	resp, err = server.RevokeToken(owner, &pb.RevokeTokenRequest{Token: "not-a-token"})
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+}
+
+func TestUserServiceServer_RevokeAllForUser(t *testing.T) {
+	server, keys := newTokenServer(t)
+	earlier := time.Now().Add(-time.Second)
+	before := issueToken(t, token.NewIssuer(keys, token.WithClock(func() time.Time { return earlier })), token.Claims{Subject: "123"})
+	otherUser := issueToken(t, token.NewIssuer(keys), token.Claims{Subject: "456"})
+
+	user := ContextWithPrincipal(context.Background(), &Principal{ID: "456", Role: models.RoleModerator})
+	_, err := server.RevokeAllForUser(user, &pb.RevokeAllForUserRequest{UserId: "123"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	admin := ContextWithPrincipal(context.Background(), &Principal{ID: "1", Role: models.RoleAdmin})
+	_, err = server.RevokeAllForUser(admin, &pb.RevokeAllForUserRequest{UserId: "123"})
+	require.NoError(t, err)
+
+	// A new Login right after the revocation yields a working token
+	after := issueToken(t, token.NewIssuer(keys), token.Claims{Subject: "123"})
+
+	for raw, active := range map[string]bool{before: false, otherUser: true, after: true} {
+		_, err := server.VerifyToken(context.Background(), raw)
+		if active {
+			assert.NoError(t, err)
+		} else {
+			assert.ErrorIs(t, err, models.ErrInvalidToken)
+		}
+	}
+
+	_, err = server.RevokeAllForUser(admin, &pb.RevokeAllForUserRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestUserServiceServer_TokensUnimplemented(t *testing.T) {
+	server := newTestServer(t, &MockUserService{})
+
+	_, err := server.IntrospectToken(context.Background(), &pb.IntrospectTokenRequest{Token: "t"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+	_, err = server.RevokeToken(context.Background(), &pb.RevokeTokenRequest{Token: "t"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, err = server.VerifyToken(context.Background(), "t")
+	assert.Error(t, err)
+
+	server = newTestServer(t, &MockUserService{}, WithRevocationStore(nil), WithSessionStore(nil))
+	_, err = server.RevokeAllForUser(context.Background(), &pb.RevokeAllForUserRequest{UserId: "123"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestMemoryRevocationStore_Sweep(t *testing.T) {
+	store := NewMemoryRevocationStore()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, store.RevokeToken(ctx, "a", now.Add(time.Minute)))
+	revoked, err := store.IsRevoked(ctx, "a", "123", now)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, store.RevokeToken(ctx, "b", now.Add(time.Minute)))
+	assert.NotContains(t, store.tokens, "a")
+	assert.Contains(t, store.tokens, "b")
+
+	revoked, err = store.IsRevoked(ctx, "", "123", now)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestMemoryRevocationStore_RevokeAllForUserBoundary(t *testing.T) {
+	store := NewMemoryRevocationStore()
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	require.NoError(t, store.RevokeAllForUser(ctx, "123", cutoff))
+
+	for issuedAt, want := range map[time.Time]bool{
+		cutoff.Add(-time.Second): true,
+		cutoff:                   false,
+		cutoff.Add(time.Second):  false,
+	} {
+		revoked, err := store.IsRevoked(ctx, "", "123", issuedAt)
+		require.NoError(t, err)
+		assert.Equal(t, want, revoked, "issued at %s", issuedAt)
+	}
+}
+
+// failingKeySource fails every key lookup with err
+type failingKeySource struct {
+	err error
+}
+
+func (f failingKeySource) VerificationKey(ctx context.Context, id string) (*token.Key, error) {
+	return nil, f.err
+}
+
+func TestUserServiceServer_RevokeToken_VerificationFails(t *testing.T) {
+	_, keys := newTokenServer(t)
+	raw := issueToken(t, token.NewIssuer(keys), token.Claims{Subject: "123"})
+
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode codes.Code
+	}{
+		{"key lookup failed", errors.New("jwks endpoint unavailable"), codes.Internal},
+		{"canceled", context.Canceled, codes.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, &MockUserService{}, WithTokenVerifier(token.NewVerifier(failingKeySource{err: tt.err})))
+
+			_, err := server.RevokeToken(context.Background(), &pb.RevokeTokenRequest{Token: raw})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+)
+
+const (
+	// MaxSearchQueryLength is the longest SearchUsers query accepted, in bytes
+	MaxSearchQueryLength = 256
+	// DefaultSearchScanLimit is how many users the built-in search examines
+	// for adapters that do not implement Searcher
+	DefaultSearchScanLimit int64 = 10000
+
+	searchScanPageSize int64 = 100
+)
+
+// Searcher is an optional extension of UserServiceInterface for adapters
+// that can run SearchUsers in their datastore (full-text index, trigram
+// index, ...). The query is the raw text sent by the client; the cursor is
+// opaque to the server, as with CursorLister. Adapters that do not implement
+// it are searched in memory by scanning ListUsers, which is only suitable
+// for small user bases. Soft-deleted users are dropped from the results
+// either way.
+type Searcher interface {
+	SearchUsers(ctx context.Context, query string, cursor string, pageSize int64) (*models.UserSearchPageModel, error)
+}
+
+// filterDeletedResults drops the results of soft-deleted users
+func filterDeletedResults(results []*models.UserSearchResult) []*models.UserSearchResult {
+	kept := make([]*models.UserSearchResult, 0, len(results))
+	for _, result := range results {
+		if result != nil && result.User != nil && result.User.DeletedAt == nil {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
+
+// searchUsersInMemory ranks up to searchScanLimit users against query and
+// returns the requested page of the results
+func (s *UserServiceServer) searchUsersInMemory(ctx context.Context, query string, page, pageSize int64) (*models.UserSearchPageModel, bool, error) {
+	terms := searchTerms(query)
+
+	var results []*models.UserSearchResult
+	var scanned int64
+	for listPage := int64(1); scanned < s.searchScanLimit; listPage++ {
+		batch, err := s.userService.ListUsers(ctx, listPage, searchScanPageSize)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, user := range batch.Users {
+			if user.DeletedAt != nil {
+				continue
+			}
+			if score := scoreUser(terms, user); score > 0 {
+				results = append(results, &models.UserSearchResult{User: user, Score: score})
+			}
+		}
+
+		scanned += int64(len(batch.Users))
+		if len(batch.Users) == 0 || listPage >= batch.TotalPages {
+			break
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].User.ID < results[j].User.ID
+	})
+
+	start := (page - 1) * pageSize
+	if start >= int64(len(results)) {
+		return &models.UserSearchPageModel{}, false, nil
+	}
+	end := start + pageSize
+	hasMore := end < int64(len(results))
+	if !hasMore {
+		end = int64(len(results))
+	}
+
+	return &models.UserSearchPageModel{Results: results[start:end]}, hasMore, nil
+}
+
+// normalizeText folds s for comparison: compatibility decomposition,
+// removal of combining marks and lower-casing, so that "José" and "JOSE"
+// compare equal
+func normalizeText(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return norm.NFC.String(b.String())
+}
+
+// searchTerms splits s into normalized words of letters and digits
+func searchTerms(s string) []string {
+	return strings.FieldsFunc(normalizeText(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// scoreUser rates how well user matches every term, between 0 (some term
+// does not match) and 1 (every term matches a word exactly)
+func scoreUser(terms []string, user *models.UserModel) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+
+	var words []string
+	for _, field := range []string{user.FirstName, user.LastName, user.Email} {
+		words = append(words, searchTerms(field)...)
+	}
+
+	var total float64
+	for _, term := range terms {
+		var best float64
+		for _, word := range words {
+			if score := scoreTerm(term, word); score > best {
+				best = score
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+
+	return total / float64(len(terms))
+}
+
+// scoreTerm rates a single query term against a single word: exact matches
+// beat prefix matches, which beat matches within a small edit distance
+func scoreTerm(term, word string) float64 {
+	if term == word {
+		return 1
+	}
+
+	termLen := utf8.RuneCountInString(term)
+	wordLen := utf8.RuneCountInString(word)
+	if strings.HasPrefix(word, term) {
+		return 0.7 + 0.2*float64(termLen)/float64(wordLen)
+	}
+
+	maxDistance := allowedTypos(termLen)
+	if maxDistance == 0 {
+		return 0
+	}
+
+	termRunes := []rune(term)
+	wordRunes := []rune(word)
+	distance := editDistance(termRunes, wordRunes)
+	if wordLen > termLen {
+		// Also tolerate typos in a prefix, e.g. "jhon" for "johnson"
+		if d := editDistance(termRunes, wordRunes[:termLen]); d < distance {
+			distance = d
+		}
+	}
+	if distance > maxDistance {
+		return 0
+	}
+
+	return 0.5 * (1 - float64(distance)/float64(termLen+1))
+}
+
+// allowedTypos returns the edit distance tolerated for a term of n runes
+func allowedTypos(n int) int {
+	switch {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// insertions, deletions, substitutions and adjacent transpositions
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(b)]
+}
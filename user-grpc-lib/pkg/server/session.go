@@ -0,0 +1,460 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/token"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+const (
+	// DefaultRefreshTokenTTL is how long a refresh token stays valid, and so
+	// how long a session lasts without being refreshed
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+	// ManageSessionsMinRole is the role needed to list and revoke the
+	// sessions of another user; everyone may manage their own
+	ManageSessionsMinRole = models.RoleAdmin
+)
+
+// Session is a login kept alive with refresh tokens
+type Session struct {
+	ID     string
+	UserID string
+	// Tenant is the tenant the user logged in to; refreshed access tokens
+	// carry it
+	Tenant string
+	Device string
+	// IPAddress is the address the session was last used from
+	IPAddress  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+}
+
+// SessionActivity is recorded when a session refreshes its tokens
+type SessionActivity struct {
+	IPAddress  string
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+}
+
+// SessionStore keeps sessions together with a hash of their current
+// refresh token and of the ones it replaced.
+type SessionStore interface {
+	// CreateSession stores a new session whose refresh token hashes to
+	// tokenHash
+	CreateSession(ctx context.Context, session *Session, tokenHash string) error
+	// GetSession returns an unexpired session, or models.ErrNotFound
+	GetSession(ctx context.Context, id string) (*Session, error)
+	// RotateRefreshToken atomically replaces the session's current refresh
+	// token hash, tokenHash, with newHash and records the activity. If
+	// tokenHash belongs to a refresh token the session already replaced, it
+	// ends the session and fails with models.ErrTokenReused. It fails with
+	// models.ErrInvalidToken if the session does not exist, has expired or
+	// never had tokenHash.
+	RotateRefreshToken(ctx context.Context, id, tokenHash, newHash string, activity SessionActivity) (*Session, error)
+	// ListSessions returns the unexpired sessions of a user
+	ListSessions(ctx context.Context, userID string) ([]*Session, error)
+	// DeleteSession ends a session; unknown sessions are not an error
+	DeleteSession(ctx context.Context, id string) error
+}
+
+// MemorySessionStore is a SessionStore for a single replica. Expired
+// sessions are swept lazily.
+type MemorySessionStore struct {
+	mu        sync.Mutex
+	sessions  map[string]*memorySession
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memorySession struct {
+	session   Session
+	tokenHash string
+	// replaced holds the hashes of the session's earlier refresh tokens
+	replaced map[string]bool
+}
+
+// NewMemorySessionStore creates an empty in-memory store
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: make(map[string]*memorySession),
+		now:      time.Now,
+	}
+}
+
+// CreateSession implements SessionStore
+func (m *MemorySessionStore) CreateSession(ctx context.Context, session *Session, tokenHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep()
+	if _, ok := m.sessions[session.ID]; ok {
+		return fmt.Errorf("session %s: %w", session.ID, models.ErrAlreadyExists)
+	}
+	m.sessions[session.ID] = &memorySession{
+		session:   *session,
+		tokenHash: tokenHash,
+		replaced:  make(map[string]bool),
+	}
+	return nil
+}
+
+// GetSession implements SessionStore
+func (m *MemorySessionStore) GetSession(ctx context.Context, id string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.live(id)
+	if !ok {
+		return nil, fmt.Errorf("session %s: %w", id, models.ErrNotFound)
+	}
+	session := stored.session
+	return &session, nil
+}
+
+// RotateRefreshToken implements SessionStore
+func (m *MemorySessionStore) RotateRefreshToken(ctx context.Context, id, tokenHash, newHash string, activity SessionActivity) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.live(id)
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("session %s does not exist: %w", id, models.ErrInvalidToken)
+	case stored.replaced[tokenHash]:
+		delete(m.sessions, id)
+		return nil, fmt.Errorf("session %s: %w", id, models.ErrTokenReused)
+	case stored.tokenHash != tokenHash:
+		return nil, fmt.Errorf("session %s has a different refresh token: %w", id, models.ErrInvalidToken)
+	}
+
+	stored.replaced[tokenHash] = true
+	stored.tokenHash = newHash
+	stored.session.IPAddress = activity.IPAddress
+	stored.session.LastSeenAt = activity.LastSeenAt
+	stored.session.ExpiresAt = activity.ExpiresAt
+
+	session := stored.session
+	return &session, nil
+}
+
+// ListSessions implements SessionStore
+func (m *MemorySessionStore) ListSessions(ctx context.Context, userID string) ([]*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep()
+	var sessions []*Session
+	for id, stored := range m.sessions {
+		if _, ok := m.live(id); ok && stored.session.UserID == userID {
+			session := stored.session
+			sessions = append(sessions, &session)
+		}
+	}
+	return sessions, nil
+}
+
+// DeleteSession implements SessionStore
+func (m *MemorySessionStore) DeleteSession(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, id)
+	return nil
+}
+
+// live returns the session with the given ID unless it expired; m.mu must
+// be held
+func (m *MemorySessionStore) live(id string) (*memorySession, bool) {
+	stored, ok := m.sessions[id]
+	if !ok || !m.now().Before(stored.session.ExpiresAt) {
+		return nil, false
+	}
+	return stored, true
+}
+
+// sweep drops expired sessions at most once a minute; m.mu must be held
+func (m *MemorySessionStore) sweep() {
+	now := m.now()
+	if now.Sub(m.lastSweep) < time.Minute {
+		return
+	}
+	m.lastSweep = now
+
+	for id, stored := range m.sessions {
+		if !now.Before(stored.session.ExpiresAt) {
+			delete(m.sessions, id)
+		}
+	}
+}
+
+// refreshTokensEnabled reports whether the server can issue and refresh
+// refresh tokens
+func (s *UserServiceServer) refreshTokensEnabled() bool {
+	return s.tokenIssuer != nil && s.sessions != nil
+}
+
+// startSession creates a session for a user who just logged in and returns
+// its first refresh token
+func (s *UserServiceServer) startSession(ctx context.Context, userID, tenant, device string) (string, *Session, error) {
+	sessionID, err := randomHex(16)
+	if err != nil {
+		return "", nil, err
+	}
+	refreshToken, err := newRefreshToken(sessionID)
+	if err != nil {
+		return "", nil, err
+	}
+	if device == "" {
+		device = userAgent(ctx)
+	}
+
+	now := time.Now()
+	session := &Session{
+		ID:         sessionID,
+		UserID:     userID,
+		Tenant:     tenant,
+		Device:     device,
+		IPAddress:  peerAddress(ctx),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.refreshTokenTTL),
+	}
+	if err := s.sessions.CreateSession(ctx, session, hashRefreshToken(refreshToken)); err != nil {
+		return "", nil, err
+	}
+	return refreshToken, session, nil
+}
+
+// RefreshToken implements the RefreshToken gRPC method. Every refresh
+// replaces the refresh token; presenting a replaced one again ends the
+// session, since either the client or an attacker holds a stolen copy.
+func (s *UserServiceServer) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	if !s.refreshTokensEnabled() {
+		return nil, status.Error(codes.Unimplemented, "refresh tokens are not supported by this server")
+	}
+	if req.RefreshToken == "" {
+		return nil, s.convertError(models.NewValidationError("refresh_token", "must not be empty"))
+	}
+
+	sessionID, ok := parseRefreshToken(req.RefreshToken)
+	if !ok {
+		return nil, s.convertError(fmt.Errorf("malformed refresh token: %w", models.ErrInvalidToken))
+	}
+	session, err := s.sessions.GetSession(ctx, sessionID)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, s.convertError(fmt.Errorf("session ended: %w", models.ErrInvalidToken))
+	}
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+
+	// The role is read again so that role changes apply on the next refresh
+	user, err := s.userService.GetUserByID(ctx, session.UserID)
+	if err == nil && (user == nil || user.DeletedAt != nil) {
+		err = models.ErrNotFound
+	}
+	if errors.Is(err, models.ErrNotFound) {
+		_ = s.sessions.DeleteSession(ctx, session.ID)
+		return nil, s.convertError(fmt.Errorf("user no longer exists: %w", models.ErrInvalidToken))
+	}
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+
+	refreshToken, err := newRefreshToken(session.ID)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+	now := time.Now()
+	session, err = s.sessions.RotateRefreshToken(ctx, session.ID, hashRefreshToken(req.RefreshToken), hashRefreshToken(refreshToken), SessionActivity{
+		IPAddress:  peerAddress(ctx),
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.refreshTokenTTL),
+	})
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+
+	accessToken, claims, err := s.tokenIssuer.Issue(token.Claims{
+		Subject: user.ID,
+		Role:    string(user.Role),
+		Tenant:  session.Tenant,
+	})
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+
+	return &pb.RefreshTokenResponse{
+		AccessToken:           accessToken,
+		TokenType:             DefaultTokenType,
+		ExpiresAt:             timestamppb.New(claims.ExpiresAt),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(session.ExpiresAt),
+		SessionId:             session.ID,
+	}, nil
+}
+
+// ListSessions implements the ListSessions gRPC method
+func (s *UserServiceServer) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	if s.sessions == nil {
+		return nil, status.Error(codes.Unimplemented, "sessions are not supported by this server")
+	}
+
+	userID := req.UserId
+	if principal, ok := PrincipalFromContext(ctx); ok && userID == "" {
+		userID = principal.ID
+	}
+	if userID == "" {
+		return nil, s.convertError(models.NewValidationError("user_id", "must not be empty"))
+	}
+	if err := s.checkSelfOrRole(ctx, userID, ManageSessionsMinRole, "manage the sessions of others"); err != nil {
+		return nil, s.convertError(err)
+	}
+
+	sessions, err := s.sessions.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+	slices.SortFunc(sessions, func(a, b *Session) int {
+		return b.LastSeenAt.Compare(a.LastSeenAt)
+	})
+
+	resp := &pb.ListSessionsResponse{}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, s.converter.ConvertSessionToProto(session))
+	}
+	return resp, nil
+}
+
+// RevokeSession implements the RevokeSession gRPC method. It ends the
+// session, so that its refresh token no longer works; access tokens already
+// issued to it stay valid until they expire.
+func (s *UserServiceServer) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	if s.sessions == nil {
+		return nil, status.Error(codes.Unimplemented, "sessions are not supported by this server")
+	}
+	if req.SessionId == "" {
+		return nil, s.convertError(models.NewValidationError("session_id", "must not be empty"))
+	}
+
+	session, err := s.sessions.GetSession(ctx, req.SessionId)
+	if errors.Is(err, models.ErrNotFound) {
+		return &pb.RevokeSessionResponse{Success: true}, nil
+	}
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+	if err := s.checkSelfOrRole(ctx, session.UserID, ManageSessionsMinRole, "manage the sessions of others"); err != nil {
+		return nil, s.convertError(err)
+	}
+
+	if err := s.sessions.DeleteSession(ctx, session.ID); err != nil {
+		return nil, s.convertError(err)
+	}
+
+	return &pb.RevokeSessionResponse{
+		Success: true,
+	}, nil
+}
+
+// endSessions ends every session of a user
+func (s *UserServiceServer) endSessions(ctx context.Context, userID string) error {
+	sessions, err := s.sessions.ListSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if err := s.sessions.DeleteSession(ctx, session.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConvertSessionToProto converts a session to its protobuf message
+func (c *ModelConverter) ConvertSessionToProto(session *Session) *pb.Session {
+	return &pb.Session{
+		Id:         session.ID,
+		UserId:     session.UserID,
+		Device:     session.Device,
+		IpAddress:  session.IPAddress,
+		CreatedAt:  timestamppb.New(session.CreatedAt),
+		LastSeenAt: timestamppb.New(session.LastSeenAt),
+		ExpiresAt:  timestamppb.New(session.ExpiresAt),
+	}
+}
+
+// newRefreshToken creates a refresh token for a session, in the form
+// "<session ID>.<secret>"
+func newRefreshToken(sessionID string) (string, error) {
+	var secret [32]byte
+	if _, err := rand.Read(secret[:]); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return sessionID + "." + base64.RawURLEncoding.EncodeToString(secret[:]), nil
+}
+
+// parseRefreshToken returns the session ID of a refresh token
+func parseRefreshToken(refreshToken string) (string, bool) {
+	sessionID, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || sessionID == "" || secret == "" {
+		return "", false
+	}
+	return sessionID, true
+}
+
+// hashRefreshToken is what stores keep instead of the refresh token itself
+func hashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// peerAddress returns the IP address of the caller, without the port
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// userAgent returns the user agent the caller's gRPC library reported
+func userAgent(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("user-agent"); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
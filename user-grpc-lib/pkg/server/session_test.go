@@ -0,0 +1,284 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/token"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// newSessionServer returns a server issuing refresh tokens, and a verifier
+// for the access tokens it issues
+func newSessionServer(t *testing.T, mockService UserServiceInterface) (*UserServiceServer, *token.Verifier) {
+	t.Helper()
+
+	key, err := token.GenerateKey("k1", token.EdDSA)
+	require.NoError(t, err)
+	keys, err := token.NewKeySet(key)
+	require.NoError(t, err)
+
+	server := newTestServer(t, mockService, WithTokenIssuer(token.NewIssuer(keys)))
+	return server, token.NewVerifier(keys)
+}
+
+// callerContext is the context of a call from addr with the given user agent
+func callerContext(addr, userAgent string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 51234},
+	})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs("user-agent", userAgent))
+}
+
+// login logs user 123 in with a refresh token
+func login(t *testing.T, ctx context.Context, server *UserServiceServer, device string) *pb.LoginResponse {
+	t.Helper()
+
+	resp, err := server.Login(ctx, &pb.LoginRequest{
+		Email:             "test@example.com",
+		Password:          "password123",
+		IssueRefreshToken: true,
+		Device:            device,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.RefreshToken)
+	return resp
+}
+
+func assertReason(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+
+	st := status.Convert(err)
+	assert.Equal(t, code, st.Code())
+	require.NotEmpty(t, st.Details())
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, reason, info.Reason)
+}
+
+func mockLogin(mockService *MockUserService, role models.Role) {
+	user := &models.UserModel{ID: "123", Email: "test@example.com", Role: role}
+	mockService.On("Login", mock.Anything, "test@example.com", "password123").Return("access-token", nil)
+	mockService.On("GetUserByEmail", mock.Anything, "test@example.com").Return(user, nil)
+	mockService.On("GetUserByID", mock.Anything, "123").Return(user, nil)
+}
+
+func TestUserServiceServer_RefreshToken(t *testing.T) {
+	mockService := &MockUserService{}
+	mockLogin(mockService, models.RoleModerator)
+	server, verifier := newSessionServer(t, mockService)
+
+	loggedIn := login(t, callerContext("10.0.0.1", "grpc-go/1.0"), server, "")
+	assert.Equal(t, "access-token", loggedIn.AccessToken)
+	assert.NotEmpty(t, loggedIn.SessionId)
+	assert.True(t, loggedIn.RefreshTokenExpiresAt.AsTime().After(time.Now().Add(DefaultRefreshTokenTTL-time.Minute)))
+
+	refreshed, err := server.RefreshToken(callerContext("10.0.0.2", "grpc-go/1.0"), &pb.RefreshTokenRequest{RefreshToken: loggedIn.RefreshToken})
+	require.NoError(t, err)
+	assert.Equal(t, loggedIn.SessionId, refreshed.SessionId)
+	assert.Equal(t, DefaultTokenType, refreshed.TokenType)
+	assert.NotEqual(t, loggedIn.RefreshToken, refreshed.RefreshToken)
+
+	claims, err := verifier.Verify(context.Background(), refreshed.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "123", claims.Subject)
+	assert.Equal(t, string(models.RoleModerator), claims.Role)
+	assert.True(t, claims.ExpiresAt.Equal(refreshed.ExpiresAt.AsTime()))
+
+	sessions, err := server.ListSessions(context.Background(), &pb.ListSessionsRequest{UserId: "123"})
+	require.NoError(t, err)
+	require.Len(t, sessions.Sessions, 1)
+	assert.Equal(t, "10.0.0.2", sessions.Sessions[0].IpAddress)
+	assert.Equal(t, "grpc-go/1.0", sessions.Sessions[0].Device)
+
+	// Reusing the replaced refresh token ends the whole session, so the
+	// token that replaced it stops working too
+	_, err = server.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: loggedIn.RefreshToken})
+	assertReason(t, err, codes.Unauthenticated, models.ReasonTokenReused)
+
+	_, err = server.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken})
+	assertReason(t, err, codes.Unauthenticated, models.ReasonInvalidToken)
+
+	sessions, err = server.ListSessions(context.Background(), &pb.ListSessionsRequest{UserId: "123"})
+	require.NoError(t, err)
+	assert.Empty(t, sessions.Sessions)
+}
+
+func TestUserServiceServer_RefreshToken_KeepsTenant(t *testing.T) {
+	mockService := &MockAuthenticatingUserService{}
+	user := &models.UserModel{ID: "123", Email: "test@example.com", Role: models.RoleUser}
+	mockService.On("Authenticate", mock.Anything, "test@example.com", "password123").Return(&models.LoginResult{
+		Token:  "access-token",
+		User:   user,
+		Tenant: "acme",
+	}, nil)
+	mockService.On("GetUserByID", mock.Anything, "123").Return(user, nil)
+	server, verifier := newSessionServer(t, mockService)
+	loggedIn := login(t, context.Background(), server, "laptop")
+
+	refreshed, err := server.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: loggedIn.RefreshToken})
+	require.NoError(t, err)
+
+	claims, err := verifier.Verify(context.Background(), refreshed.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "123", claims.Subject)
+	assert.Equal(t, "acme", claims.Tenant)
+}
+
+func TestUserServiceServer_RefreshToken_Invalid(t *testing.T) {
+	mockService := &MockUserService{}
+	mockLogin(mockService, models.RoleUser)
+	server, _ := newSessionServer(t, mockService)
+	loggedIn := login(t, context.Background(), server, "laptop")
+
+	tests := []struct {
+		name         string
+		refreshToken string
+		expectedCode codes.Code
+	}{
+		{"empty", "", codes.InvalidArgument},
+		{"malformed", "garbage", codes.Unauthenticated},
+		{"unknown session", "abc.def", codes.Unauthenticated},
+		{"wrong secret", loggedIn.SessionId + ".guessed", codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: tt.refreshToken})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+
+	// Guessing does not end the session
+	_, err := server.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: loggedIn.RefreshToken})
+	assert.NoError(t, err)
+}
+
+func TestUserServiceServer_RefreshToken_DeletedUser(t *testing.T) {
+	tests := []struct {
+		name string
+		user *models.UserModel
+	}{
+		{"soft-deleted", &models.UserModel{ID: "123", DeletedAt: timestamppb.Now()}},
+		{"missing", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserService{}
+			mockService.On("Login", mock.Anything, "test@example.com", "password123").Return("access-token", nil)
+			mockService.On("GetUserByEmail", mock.Anything, "test@example.com").Return(&models.UserModel{ID: "123"}, nil)
+			mockService.On("GetUserByID", mock.Anything, "123").Return(tt.user, nil)
+			server, _ := newSessionServer(t, mockService)
+			loggedIn := login(t, context.Background(), server, "laptop")
+
+			_, err := server.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: loggedIn.RefreshToken})
+			assertReason(t, err, codes.Unauthenticated, models.ReasonInvalidToken)
+
+			_, err = server.sessions.GetSession(context.Background(), loggedIn.SessionId)
+			assert.ErrorIs(t, err, models.ErrNotFound)
+		})
+	}
+}
+
+func TestUserServiceServer_RefreshTokensUnimplemented(t *testing.T) {
+	server := newTestServer(t, &MockUserService{})
+
+	_, err := server.Login(context.Background(), &pb.LoginRequest{Email: "test@example.com", Password: "password123", IssueRefreshToken: true})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+	_, err = server.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: "a.b"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	server = newTestServer(t, &MockUserService{}, WithSessionStore(nil))
+	_, err = server.ListSessions(context.Background(), &pb.ListSessionsRequest{UserId: "123"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+	_, err = server.RevokeSession(context.Background(), &pb.RevokeSessionRequest{SessionId: "s"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestUserServiceServer_Sessions(t *testing.T) {
+	mockService := &MockUserService{}
+	mockLogin(mockService, models.RoleUser)
+	server, _ := newSessionServer(t, mockService)
+
+	laptop := login(t, callerContext("10.0.0.1", "grpc-go/1.0"), server, "laptop")
+	phone := login(t, callerContext("2001:db8::1", "grpc-go/1.0"), server, "phone")
+
+	owner := ContextWithPrincipal(context.Background(), &Principal{ID: "123", Role: models.RoleUser})
+	resp, err := server.ListSessions(owner, &pb.ListSessionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Sessions, 2)
+	assert.Equal(t, phone.SessionId, resp.Sessions[0].Id)
+	assert.Equal(t, "phone", resp.Sessions[0].Device)
+	assert.Equal(t, "2001:db8::1", resp.Sessions[0].IpAddress)
+	assert.Equal(t, laptop.SessionId, resp.Sessions[1].Id)
+	assert.Equal(t, "10.0.0.1", resp.Sessions[1].IpAddress)
+
+	other := ContextWithPrincipal(context.Background(), &Principal{ID: "456", Role: models.RoleModerator})
+	_, err = server.ListSessions(other, &pb.ListSessionsRequest{UserId: "123"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = server.RevokeSession(other, &pb.RevokeSessionRequest{SessionId: laptop.SessionId})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	revoked, err := server.RevokeSession(owner, &pb.RevokeSessionRequest{SessionId: laptop.SessionId})
+	require.NoError(t, err)
+	assert.True(t, revoked.Success)
+	_, err = server.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: laptop.RefreshToken})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Revoking again, or a session that never existed, also succeeds
+	revoked, err = server.RevokeSession(other, &pb.RevokeSessionRequest{SessionId: laptop.SessionId})
+	require.NoError(t, err)
+	assert.True(t, revoked.Success)
+
+	admin := ContextWithPrincipal(context.Background(), &Principal{ID: "1", Role: models.RoleAdmin})
+	_, err = server.RevokeAllForUser(admin, &pb.RevokeAllForUserRequest{UserId: "123"})
+	require.NoError(t, err)
+	resp, err = server.ListSessions(admin, &pb.ListSessionsRequest{UserId: "123"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Sessions)
+
+	_, err = server.ListSessions(context.Background(), &pb.ListSessionsRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestMemorySessionStore_Expiry(t *testing.T) {
+	store := NewMemorySessionStore()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, store.CreateSession(ctx, &Session{ID: "a", UserID: "123", ExpiresAt: now.Add(time.Hour)}, "hash-1"))
+
+	session, err := store.RotateRefreshToken(ctx, "a", "hash-1", "hash-2", SessionActivity{IPAddress: "10.0.0.1", LastSeenAt: now, ExpiresAt: now.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(2*time.Hour), session.ExpiresAt)
+
+	now = now.Add(90 * time.Minute)
+	_, err = store.GetSession(ctx, "a")
+	require.NoError(t, err)
+
+	now = now.Add(time.Hour)
+	_, err = store.GetSession(ctx, "a")
+	assert.ErrorIs(t, err, models.ErrNotFound)
+	_, err = store.RotateRefreshToken(ctx, "a", "hash-2", "hash-3", SessionActivity{})
+	assert.ErrorIs(t, err, models.ErrInvalidToken)
+
+	sessions, err := store.ListSessions(ctx, "123")
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+	assert.Empty(t, store.sessions)
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// DefaultUpdatableUserPaths are the fields UpdateUser changes unless
+// WithUpdatableUserPaths says otherwise; every adapter supports them
+var DefaultUpdatableUserPaths = []string{models.UserPathFirstName, models.UserPathLastName}
+
+// settableUserPaths are the fields UserUpdateInput can carry
+var settableUserPaths = map[string]bool{
+	models.UserPathFirstName: true,
+	models.UserPathLastName:  true,
+	models.UserPathEmail:     true,
+	models.UserPathRating:    true,
+}
+
+// immutableUserPaths are fields of User that UpdateUser never changes
+var immutableUserPaths = map[string]bool{
+	"id":         true,
+	"role":       true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+	"etag":       true,
+}
+
+// parseUserUpdate builds the adapter input from either the user and
+// update_mask fields or the deprecated optional fields of req
+func (s *UserServiceServer) parseUserUpdate(req *pb.UpdateUserRequest) (models.UserUpdateInput, error) {
+	if req.User == nil && req.UpdateMask == nil {
+		return legacyUserUpdate(req), nil
+	}
+	if req.FirstName != nil || req.LastName != nil {
+		return models.UserUpdateInput{}, models.NewValidationError("user", "cannot be combined with the deprecated first_name and last_name fields")
+	}
+
+	user := req.User
+	if user == nil {
+		user = &pb.User{}
+	}
+
+	paths, err := s.updatePaths(user, req.UpdateMask.GetPaths())
+	if err != nil {
+		return models.UserUpdateInput{}, err
+	}
+
+	input := models.UserUpdateInput{Paths: paths}
+	for _, path := range paths {
+		switch path {
+		case models.UserPathFirstName:
+			input.FirstName = &user.FirstName
+		case models.UserPathLastName:
+			input.LastName = &user.LastName
+		case models.UserPathEmail:
+			input.Email = &user.Email
+		case models.UserPathRating:
+			input.Rating = &user.Rating
+		}
+	}
+
+	return input, validateUserUpdate(input)
+}
+
+// updatePaths validates and normalizes the paths of an update mask. An empty
+// mask selects the updatable fields that are set in user.
+func (s *UserServiceServer) updatePaths(user *pb.User, maskPaths []string) ([]string, error) {
+	if len(maskPaths) == 0 {
+		var paths []string
+		for _, path := range s.updatableUserPaths {
+			if isUserFieldSet(user, path) {
+				paths = append(paths, path)
+			}
+		}
+		if len(paths) == 0 {
+			return nil, models.NewValidationError("update_mask", "no fields to update")
+		}
+		return paths, nil
+	}
+
+	if len(maskPaths) == 1 && maskPaths[0] == "*" {
+		return append([]string(nil), s.updatableUserPaths...), nil
+	}
+
+	var errs []error
+	var paths []string
+	seen := make(map[string]bool)
+	for _, path := range maskPaths {
+		switch {
+		case seen[path]:
+			continue
+		case immutableUserPaths[path]:
+			errs = append(errs, models.NewValidationError("update_mask", fmt.Sprintf("path %q is immutable", path)))
+		case !s.isUpdatablePath(path):
+			errs = append(errs, models.NewValidationError("update_mask", fmt.Sprintf("path %q cannot be updated", path)))
+		default:
+			paths = append(paths, path)
+		}
+		seen[path] = true
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return paths, nil
+}
+
+func (s *UserServiceServer) isUpdatablePath(path string) bool {
+	for _, p := range s.updatableUserPaths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// isUserFieldSet reports whether the field at path has a non-zero value
+func isUserFieldSet(user *pb.User, path string) bool {
+	switch path {
+	case models.UserPathFirstName:
+		return user.FirstName != ""
+	case models.UserPathLastName:
+		return user.LastName != ""
+	case models.UserPathEmail:
+		return user.Email != ""
+	case models.UserPathRating:
+		return user.Rating != 0
+	default:
+		return false
+	}
+}
+
+// legacyUserUpdate converts the deprecated optional fields of req
+func legacyUserUpdate(req *pb.UpdateUserRequest) models.UserUpdateInput {
+	input := models.UserUpdateInput{}
+	if req.FirstName != nil {
+		input.FirstName = req.FirstName
+		input.Paths = append(input.Paths, models.UserPathFirstName)
+	}
+	if req.LastName != nil {
+		input.LastName = req.LastName
+		input.Paths = append(input.Paths, models.UserPathLastName)
+	}
+	return input
+}
+
+// validateUserUpdate checks the new values of the fields being updated
+func validateUserUpdate(input models.UserUpdateInput) error {
+	var errs []error
+	if input.FirstName != nil && strings.TrimSpace(*input.FirstName) == "" {
+		errs = append(errs, models.NewValidationError("user.first_name", "must not be empty"))
+	}
+	if input.LastName != nil && strings.TrimSpace(*input.LastName) == "" {
+		errs = append(errs, models.NewValidationError("user.last_name", "must not be empty"))
+	}
+	if input.Email != nil {
+		if addr, err := mail.ParseAddress(*input.Email); err != nil || addr.Address != *input.Email {
+			errs = append(errs, models.NewValidationError("user.email", "must be a valid email address"))
+		}
+	}
+	return errors.Join(errs...)
+}
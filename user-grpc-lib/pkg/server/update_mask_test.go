@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+func TestUserServiceServer_UpdateUser_FieldMask(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	int32Ptr := func(i int32) *int32 { return &i }
+
+	tests := []struct {
+		name          string
+		opts          []Option
+		request       *pb.UpdateUserRequest
+		expectedInput models.UserUpdateInput
+		expectedError codes.Code
+	}{
+		{
+			name: "mask selects fields",
+			request: &pb.UpdateUserRequest{
+				Id:         "123",
+				User:       &pb.User{FirstName: "Jane", LastName: "Ignored"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"first_name", "first_name"}},
+			},
+			expectedInput: models.UserUpdateInput{FirstName: strPtr("Jane"), Paths: []string{"first_name"}},
+		},
+		{
+			name: "empty mask updates set fields",
+			request: &pb.UpdateUserRequest{
+				Id:   "123",
+				User: &pb.User{LastName: "Roe", Email: "ignored@example.com"},
+			},
+			expectedInput: models.UserUpdateInput{LastName: strPtr("Roe"), Paths: []string{"last_name"}},
+		},
+		{
+			name: "wildcard with extra updatable paths",
+			opts: []Option{WithUpdatableUserPaths("email", "rating", "role")},
+			request: &pb.UpdateUserRequest{
+				Id:         "123",
+				User:       &pb.User{Email: "jane@example.com"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"*"}},
+			},
+			expectedInput: models.UserUpdateInput{
+				Email:  strPtr("jane@example.com"),
+				Rating: int32Ptr(0),
+				Paths:  []string{"email", "rating"},
+			},
+		},
+		{
+			name: "immutable path",
+			request: &pb.UpdateUserRequest{
+				Id:         "123",
+				User:       &pb.User{Role: pb.Role_ROLE_ADMIN},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"role"}},
+			},
+			expectedError: codes.InvalidArgument,
+		},
+		{
+			name: "path not updatable by default",
+			request: &pb.UpdateUserRequest{
+				Id:         "123",
+				User:       &pb.User{Email: "jane@example.com"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"email"}},
+			},
+			expectedError: codes.InvalidArgument,
+		},
+		{
+			name: "clearing a required field",
+			request: &pb.UpdateUserRequest{
+				Id:         "123",
+				User:       &pb.User{},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"last_name"}},
+			},
+			expectedError: codes.InvalidArgument,
+		},
+		{
+			name: "nothing to update",
+			request: &pb.UpdateUserRequest{
+				Id:   "123",
+				User: &pb.User{},
+			},
+			expectedError: codes.InvalidArgument,
+		},
+		{
+			name: "mixed with deprecated fields",
+			request: &pb.UpdateUserRequest{
+				Id:         "123",
+				FirstName:  strPtr("Jane"),
+				User:       &pb.User{LastName: "Roe"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"last_name"}},
+			},
+			expectedError: codes.InvalidArgument,
+		},
+		{
+			name: "deprecated fields",
+			request: &pb.UpdateUserRequest{
+				Id:        "123",
+				FirstName: strPtr("Jane"),
+			},
+			expectedInput: models.UserUpdateInput{FirstName: strPtr("Jane"), Paths: []string{"first_name"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserService{}
+			if tt.expectedError == codes.OK {
+				mockService.On("UpdateUser", mock.Anything, "123", tt.expectedInput).Return(&models.UserModel{ID: "123"}, nil)
+			}
+
+			server := newTestServer(t, mockService, tt.opts...)
+
+			_, err := server.UpdateUser(context.Background(), tt.request)
+
+			assert.Equal(t, tt.expectedError, status.Code(err))
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -2,12 +2,19 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/token"
 	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
 )
 
@@ -23,6 +30,18 @@ type UserServiceInterface interface {
 	UpdatePassword(ctx context.Context, id string, input models.UserPasswordUpdateInput) error
 }
 
+// Authenticator is an optional extension of UserServiceInterface for adapters
+// that can report token metadata alongside the token itself. When the adapter
+// does not implement it, Login falls back to UserServiceInterface.Login and
+// looks the user up by email. The Tenant of the result is kept with the
+// sessions Login starts and carried into the access tokens they refresh.
+type Authenticator interface {
+	Authenticate(ctx context.Context, email, password string) (*models.LoginResult, error)
+}
+
+// DefaultTokenType is reported to clients when the adapter does not set one
+const DefaultTokenType = "Bearer"
+
 // ModelConverter handles conversion between domain models and gRPC messages
 type ModelConverter struct{}
 
@@ -46,6 +65,7 @@ func (c *ModelConverter) ConvertUserToProto(user *models.UserModel) *pb.User {
 		UpdatedAt: user.UpdatedAt,
 		DeletedAt: user.DeletedAt,
 		Rating:    user.Rating,
+		Etag:      user.CurrentETag(),
 	}
 }
 
@@ -82,18 +102,81 @@ type UserServiceServer struct {
 	pb.UnimplementedUserServiceServer
 	userService UserServiceInterface
 	converter   *ModelConverter
+	pageTokens  *pageTokenCodec
+	maxPageSize int64
+
+	searchScanLimit int64
+
+	maxBatchSize     int
+	batchParallelism int
+
+	eventSource UserEventSource
+
+	updatableUserPaths []string
+
+	idempotency    IdempotencyStore
+	fingerprintKey []byte
+
+	signingKeys   *token.KeySet
+	tokenVerifier *token.Verifier
+	revocations   RevocationStore
+
+	tokenIssuer     *token.Issuer
+	sessions        SessionStore
+	refreshTokenTTL time.Duration
+
+	// optionErrs collects invalid options for NewUserServiceServer to report
+	optionErrs []error
 }
 
-// NewUserServiceServer creates a new gRPC user service server
-func NewUserServiceServer(userService UserServiceInterface) *UserServiceServer {
-	return &UserServiceServer{
+// NewUserServiceServer creates a new gRPC user service server. It fails if
+// an option was given an invalid value.
+func NewUserServiceServer(userService UserServiceInterface, opts ...Option) (*UserServiceServer, error) {
+	s := &UserServiceServer{
 		userService: userService,
 		converter:   NewModelConverter(),
+		maxPageSize: DefaultMaxPageSize,
+
+		searchScanLimit: DefaultSearchScanLimit,
+
+		maxBatchSize:     DefaultMaxBatchSize,
+		batchParallelism: DefaultBatchParallelism,
+
+		updatableUserPaths: DefaultUpdatableUserPaths,
+
+		idempotency: NewMemoryIdempotencyStore(DefaultIdempotencyTTL),
+		revocations: NewMemoryRevocationStore(),
+
+		sessions:        NewMemorySessionStore(),
+		refreshTokenTTL: DefaultRefreshTokenTTL,
+	}
+	if source, ok := userService.(UserEventSource); ok {
+		s.eventSource = source
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := errors.Join(s.optionErrs...); err != nil {
+		return nil, err
+	}
+	if s.pageTokens == nil {
+		s.pageTokens = newRandomPageTokenCodec()
+	}
+	if s.fingerprintKey == nil {
+		s.fingerprintKey = newRandomFingerprintKey()
 	}
+	return s, nil
 }
 
-// CreateUser implements the CreateUser gRPC method
+// CreateUser implements the CreateUser gRPC method.
+// Requests with a request_id are applied at most once.
 func (s *UserServiceServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	return idempotent(ctx, s, "CreateUser", req, func(ctx context.Context) (*pb.CreateUserResponse, error) {
+		return s.createUser(ctx, req)
+	})
+}
+
+func (s *UserServiceServer) createUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
 	if req.Email == "" || req.Password == "" || req.FirstName == "" || req.LastName == "" {
 		return nil, status.Error(codes.InvalidArgument, "missing required fields")
 	}
@@ -120,15 +203,24 @@ func (s *UserServiceServer) GetUserByEmail(ctx context.Context, req *pb.GetUserB
 	if req.Email == "" {
 		return nil, status.Error(codes.InvalidArgument, "email is required")
 	}
+	mask, err := parseReadMask(req.ReadMask)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
 
-	user, err := s.userService.GetUserByEmail(ctx, req.Email)
+	user, err := s.userService.GetUserByEmail(mask.hint(ctx), req.Email)
 	if err != nil {
 		return nil, s.convertError(err)
 	}
+	if user != nil && user.DeletedAt != nil && !req.IncludeDeleted {
+		return nil, s.convertError(fmt.Errorf("user %s: %w", req.Email, models.ErrNotFound))
+	}
 
-	return &pb.GetUserByEmailResponse{
+	resp := &pb.GetUserByEmailResponse{
 		User: s.converter.ConvertUserToProto(user),
-	}, nil
+	}
+	mask.apply(resp.User)
+	return resp, nil
 }
 
 // GetUserByID implements the GetUserByID gRPC method
@@ -136,63 +228,304 @@ func (s *UserServiceServer) GetUserByID(ctx context.Context, req *pb.GetUserByID
 	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "id is required")
 	}
+	mask, err := parseReadMask(req.ReadMask)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
 
-	user, err := s.userService.GetUserByID(ctx, req.Id)
+	user, err := s.userService.GetUserByID(mask.hint(ctx), req.Id)
 	if err != nil {
 		return nil, s.convertError(err)
 	}
+	if user != nil && user.DeletedAt != nil && !req.IncludeDeleted {
+		return nil, s.convertError(fmt.Errorf("user %s: %w", req.Id, models.ErrNotFound))
+	}
 
-	return &pb.GetUserByIDResponse{
+	resp := &pb.GetUserByIDResponse{
 		User: s.converter.ConvertUserToProto(user),
-	}, nil
+	}
+	mask.apply(resp.User)
+	return resp, nil
 }
 
-// GetUsers implements the GetUsers gRPC method
+// GetUsers implements the GetUsers gRPC method. Requests carrying a
+// page_token, or no page number when the adapter implements CursorLister,
+// use cursor pagination; otherwise the legacy page/page_size offsets apply.
+// Both modes return a next_page_token while more results remain.
 func (s *UserServiceServer) GetUsers(ctx context.Context, req *pb.GetUsersRequest) (*pb.GetUsersResponse, error) {
+	mask, err := parseReadMask(req.ReadMask)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+
+	resp, err := s.getUsers(mask.hint(ctx), req)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range resp.Users {
+		mask.apply(user)
+	}
+	return resp, nil
+}
+
+// getUsers lists a page of users for GetUsers
+func (s *UserServiceServer) getUsers(ctx context.Context, req *pb.GetUsersRequest) (*pb.GetUsersResponse, error) {
+	pageSize := s.normalizePageSize(req.PageSize)
+
+	query, err := s.parseUserQuery(req.Filter, req.OrderBy)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+
+	list := &listUsersRequest{
+		query:       query,
+		fingerprint: fingerprint(query, query.IsZero()),
+		pageSize:    pageSize,
+	}
+
+	cursorLister, hasCursors := s.userService.(CursorLister)
+
+	if req.PageToken != "" {
+		token, err := s.pageTokens.decode(req.PageToken)
+		if err != nil || (token.Page <= 0 && !hasCursors) {
+			return nil, s.convertError(models.NewValidationError("page_token", "is invalid"))
+		}
+		if token.Query != list.fingerprint {
+			return nil, s.convertError(models.NewValidationError("page_token", "does not match filter and order_by of the request"))
+		}
+		if token.Page > 0 {
+			return s.listUsersByOffset(ctx, list, token.Page)
+		}
+		return s.listUsersByCursor(ctx, cursorLister, list, token.Cursor)
+	}
+
+	if req.Page <= 0 && hasCursors {
+		return s.listUsersByCursor(ctx, cursorLister, list, "")
+	}
+
 	page := req.Page
 	if page <= 0 {
 		page = 1
 	}
+	return s.listUsersByOffset(ctx, list, page)
+}
 
-	pageSize := req.PageSize
-	if pageSize <= 0 {
-		pageSize = 10
+// SearchUsers implements the SearchUsers gRPC method
+func (s *UserServiceServer) SearchUsers(ctx context.Context, req *pb.SearchUsersRequest) (*pb.SearchUsersResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	switch {
+	case query == "":
+		return nil, s.convertError(models.NewValidationError("query", "is required"))
+	case len(query) > MaxSearchQueryLength:
+		return nil, s.convertError(models.NewValidationError("query", fmt.Sprintf("must be at most %d bytes", MaxSearchQueryLength)))
+	case len(searchTerms(query)) == 0:
+		return nil, s.convertError(models.NewValidationError("query", "must contain letters or digits"))
+	}
+
+	pageSize := s.normalizePageSize(req.PageSize)
+	queryFingerprint := fingerprint(normalizeText(query), false)
+	searcher, hasSearcher := s.userService.(Searcher)
+
+	var token pageToken
+	if req.PageToken != "" {
+		var err error
+		token, err = s.pageTokens.decode(req.PageToken)
+		// Searcher tokens carry a cursor, in-memory search tokens a page number
+		if err != nil || token.Query != queryFingerprint || (token.Page > 0) == hasSearcher {
+			return nil, s.convertError(models.NewValidationError("page_token", "is invalid"))
+		}
+	}
+
+	var result *models.UserSearchPageModel
+	var next pageToken
+	if hasSearcher {
+		var err error
+		result, err = searcher.SearchUsers(ctx, query, token.Cursor, pageSize)
+		if err != nil {
+			return nil, s.convertError(err)
+		}
+		result.Results = filterDeletedResults(result.Results)
+		if result.NextCursor != "" {
+			next = pageToken{Cursor: result.NextCursor, Query: queryFingerprint}
+		}
+	} else {
+		page := max(token.Page, 1)
+		var hasMore bool
+		var err error
+		result, hasMore, err = s.searchUsersInMemory(ctx, query, page, pageSize)
+		if err != nil {
+			return nil, s.convertError(err)
+		}
+		if hasMore {
+			next = pageToken{Page: page + 1, Query: queryFingerprint}
+		}
+	}
+
+	var nextPageToken string
+	if next != (pageToken{}) {
+		var err error
+		nextPageToken, err = s.pageTokens.encode(next)
+		if err != nil {
+			return nil, s.convertError(err)
+		}
+	}
+
+	results := make([]*pb.UserSearchResult, len(result.Results))
+	for i, r := range result.Results {
+		results[i] = &pb.UserSearchResult{
+			User:  s.converter.ConvertUserToProto(r.User),
+			Score: r.Score,
+		}
+	}
+
+	return &pb.SearchUsersResponse{
+		Results:       results,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// BatchGetUsers implements the BatchGetUsers gRPC method
+func (s *UserServiceServer) BatchGetUsers(ctx context.Context, req *pb.BatchGetUsersRequest) (*pb.BatchGetUsersResponse, error) {
+	ids := dedupeIDs(req.Ids)
+	if len(ids) == 0 {
+		return nil, s.convertError(models.NewValidationError("ids", "at least one id is required"))
+	}
+	if len(ids) > s.maxBatchSize {
+		return nil, s.convertError(models.NewValidationError("ids", fmt.Sprintf("at most %d distinct ids are allowed", s.maxBatchSize)))
+	}
+	for _, id := range ids {
+		if id == "" {
+			return nil, s.convertError(models.NewValidationError("ids", "must not contain empty ids"))
+		}
 	}
 
-	result, err := s.userService.ListUsers(ctx, page, pageSize)
+	mask, err := parseReadMask(req.ReadMask)
 	if err != nil {
 		return nil, s.convertError(err)
 	}
 
-	users := make([]*pb.User, len(result.Users))
-	for i, user := range result.Users {
-		users[i] = s.converter.ConvertUserToProto(user)
+	found, err := s.getUsersByIDs(mask.hint(ctx), ids)
+	if err != nil {
+		return nil, s.convertError(err)
 	}
 
-	return &pb.GetUsersResponse{
-		Users:      users,
-		Total:      result.Total,
-		Page:       result.Page,
-		PageSize:   result.PageSize,
-		TotalPages: result.TotalPages,
-	}, nil
+	resp := &pb.BatchGetUsersResponse{
+		Users: make(map[string]*pb.User, len(found)),
+	}
+	for _, id := range ids {
+		if user, ok := found[id]; ok && user != nil && (user.DeletedAt == nil || req.IncludeDeleted) {
+			resp.Users[id] = s.converter.ConvertUserToProto(user)
+			mask.apply(resp.Users[id])
+		} else {
+			resp.MissingIds = append(resp.MissingIds, id)
+		}
+	}
+
+	return resp, nil
+}
+
+// ExportUsers implements the ExportUsers gRPC method. Users are sent one at
+// a time, so a slow reader applies backpressure through gRPC flow control
+// instead of the whole table being buffered in memory.
+func (s *UserServiceServer) ExportUsers(req *pb.ExportUsersRequest, stream pb.UserService_ExportUsersServer) error {
+	query, err := s.parseUserQuery(req.Filter, "")
+	if err != nil {
+		return s.convertError(err)
+	}
+
+	resume := pageToken{Query: fingerprint(query, query.IsZero())}
+	if req.ResumeToken != "" {
+		token, err := s.pageTokens.decode(req.ResumeToken)
+		if err != nil || token.Query != resume.Query {
+			return s.convertError(models.NewValidationError("resume_token", "is invalid or does not match the filter"))
+		}
+		resume = token
+	}
+
+	err = s.exportUsers(stream.Context(), query, resume, func(user *models.UserModel, token pageToken) error {
+		return s.sendExportedUser(stream, user, token)
+	})
+	if err != nil {
+		return s.convertError(err)
+	}
+	return nil
+}
+
+// WatchUsers implements the WatchUsers gRPC method. Events are delivered
+// until the client cancels the call or the event source ends the
+// subscription, e.g. because the client fell too far behind.
+func (s *UserServiceServer) WatchUsers(req *pb.WatchUsersRequest, stream pb.UserService_WatchUsersServer) error {
+	if s.eventSource == nil {
+		return status.Error(codes.Unimplemented, "watching users is not supported by this server")
+	}
+
+	types, err := parseEventTypes(req.Types)
+	if err != nil {
+		return s.convertError(err)
+	}
+	if req.AfterRevision < 0 {
+		return s.convertError(models.NewValidationError("after_revision", "must not be negative"))
+	}
+
+	ctx := stream.Context()
+	sub, err := s.eventSource.Subscribe(ctx, req.AfterRevision)
+	if err != nil {
+		return s.convertError(err)
+	}
+	defer sub.Close()
+
+	for event := range sub.Events() {
+		msg := s.converter.ConvertUserEventToProto(event)
+		if types != nil && !types[msg.Type] {
+			continue
+		}
+		if err := stream.Send(&pb.WatchUsersResponse{Event: msg}); err != nil {
+			return err
+		}
+	}
+
+	if err := sub.Err(); err != nil {
+		return s.convertError(err)
+	}
+	return s.convertError(ctx.Err())
+}
+
+// normalizePageSize applies the default and maximum page sizes
+func (s *UserServiceServer) normalizePageSize(pageSize int64) int64 {
+	if pageSize <= 0 {
+		return DefaultPageSize
+	}
+	return min(pageSize, s.maxPageSize)
+}
+
+// convertUsersToProto converts a slice of domain users to protobuf messages
+func (s *UserServiceServer) convertUsersToProto(users []*models.UserModel) []*pb.User {
+	result := make([]*pb.User, len(users))
+	for i, user := range users {
+		result[i] = s.converter.ConvertUserToProto(user)
+	}
+	return result
 }
 
-// UpdateUser implements the UpdateUser gRPC method
+// UpdateUser implements the UpdateUser gRPC method.
+// Requests with a request_id are applied at most once.
 func (s *UserServiceServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	return idempotent(ctx, s, "UpdateUser", req, func(ctx context.Context) (*pb.UpdateUserResponse, error) {
+		return s.updateUser(ctx, req)
+	})
+}
+
+func (s *UserServiceServer) updateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
 	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "id is required")
 	}
 
-	input := models.UserUpdateInput{}
-	if req.FirstName != nil {
-		input.FirstName = req.FirstName
-	}
-	if req.LastName != nil {
-		input.LastName = req.LastName
+	input, err := s.parseUserUpdate(req)
+	if err != nil {
+		return nil, s.convertError(err)
 	}
 
-	user, err := s.userService.UpdateUser(ctx, req.Id, input)
+	user, err := s.updateUserIfMatch(ctx, req.Id, input, req.ExpectedEtag)
 	if err != nil {
 		return nil, s.convertError(err)
 	}
@@ -202,15 +535,26 @@ func (s *UserServiceServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRe
 	}, nil
 }
 
-// DeleteUser implements the DeleteUser gRPC method
+// DeleteUser implements the DeleteUser gRPC method.
+// Requests with a request_id are applied at most once.
 func (s *UserServiceServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
-	if req.Id == "" || req.ActorId == "" {
+	return idempotent(ctx, s, "DeleteUser", req, func(ctx context.Context) (*pb.DeleteUserResponse, error) {
+		return s.deleteUser(ctx, req)
+	})
+}
+
+func (s *UserServiceServer) deleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	actorID, protoActorRole, err := s.resolveActor(ctx, req.ActorId, req.ActorRole)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+	if req.Id == "" || actorID == "" {
 		return nil, status.Error(codes.InvalidArgument, "id and actor_id are required")
 	}
 
-	actorRole := s.converter.ConvertRoleFromProto(req.ActorRole)
+	actorRole := s.converter.ConvertRoleFromProto(protoActorRole)
 
-	err := s.userService.DeleteUser(ctx, req.Id, req.ActorId, actorRole)
+	err = s.deleteUserIfMatch(ctx, req.Id, actorID, actorRole, req.ExpectedEtag)
 	if err != nil {
 		return nil, s.convertError(err)
 	}
@@ -220,43 +564,255 @@ func (s *UserServiceServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRe
 	}, nil
 }
 
-// convertError converts domain errors to appropriate gRPC status codes
-func (s *UserServiceServer) convertError(err error) error {
-	if err == nil {
-		return nil
+// UpdateUserRole implements the UpdateUserRole gRPC method.
+// Requests with a request_id are applied at most once.
+func (s *UserServiceServer) UpdateUserRole(ctx context.Context, req *pb.UpdateUserRoleRequest) (*pb.UpdateUserRoleResponse, error) {
+	return idempotent(ctx, s, "UpdateUserRole", req, func(ctx context.Context) (*pb.UpdateUserRoleResponse, error) {
+		return s.updateUserRole(ctx, req)
+	})
+}
+
+func (s *UserServiceServer) updateUserRole(ctx context.Context, req *pb.UpdateUserRoleRequest) (*pb.UpdateUserRoleResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	// ConvertRoleFromProto falls back to RoleUser, so unspecified roles must
+	// be rejected here rather than silently demoting the user
+	if !isSpecifiedRole(req.Role) {
+		return nil, status.Error(codes.InvalidArgument, "role is required")
+	}
+
+	_, protoActorRole, err := s.resolveActor(ctx, "", req.ActorRole)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+	if !isSpecifiedRole(protoActorRole) {
+		return nil, status.Error(codes.InvalidArgument, "actor_role is required")
 	}
 
-	errMsg := err.Error()
+	role := s.converter.ConvertRoleFromProto(req.Role)
+	actorRole := s.converter.ConvertRoleFromProto(protoActorRole)
 
-	// Map common business logic errors to gRPC status codes
-	switch {
-	case contains(errMsg, "not found"):
-		return status.Error(codes.NotFound, errMsg)
-	case contains(errMsg, "already exists"):
-		return status.Error(codes.AlreadyExists, errMsg)
-	case contains(errMsg, "invalid credentials"):
-		return status.Error(codes.Unauthenticated, errMsg)
-	case contains(errMsg, "insufficient rights"):
-		return status.Error(codes.PermissionDenied, errMsg)
-	case contains(errMsg, "validation"):
-		return status.Error(codes.InvalidArgument, errMsg)
+	err = s.updateUserRoleIfMatch(ctx, req.Id, role, actorRole, req.ExpectedEtag)
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+
+	return &pb.UpdateUserRoleResponse{
+		Success: true,
+	}, nil
+}
+
+// UpdatePassword implements the UpdatePassword gRPC method.
+// Requests with a request_id are applied at most once.
+func (s *UserServiceServer) UpdatePassword(ctx context.Context, req *pb.UpdatePasswordRequest) (*pb.UpdatePasswordResponse, error) {
+	return idempotent(ctx, s, "UpdatePassword", req, func(ctx context.Context) (*pb.UpdatePasswordResponse, error) {
+		return s.updatePassword(ctx, req)
+	})
+}
+
+func (s *UserServiceServer) updatePassword(ctx context.Context, req *pb.UpdatePasswordRequest) (*pb.UpdatePasswordResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		return nil, status.Error(codes.InvalidArgument, "current_password and new_password are required")
+	}
+	if req.CurrentPassword == req.NewPassword {
+		return nil, status.Error(codes.InvalidArgument, "new_password must differ from current_password")
+	}
+
+	input := models.UserPasswordUpdateInput{
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	}
+
+	err := s.userService.UpdatePassword(ctx, req.Id, input)
+	if err != nil {
+		return nil, s.convertPasswordError(err, input)
+	}
+
+	return &pb.UpdatePasswordResponse{
+		Success: true,
+	}, nil
+}
+
+// Login implements the Login gRPC method. With issue_refresh_token it also
+// starts a session and returns its first refresh token.
+func (s *UserServiceServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	if req.Email == "" || req.Password == "" {
+		return nil, status.Error(codes.InvalidArgument, "email and password are required")
+	}
+	if req.IssueRefreshToken && !s.refreshTokensEnabled() {
+		return nil, status.Error(codes.Unimplemented, "refresh tokens are not supported by this server")
+	}
+
+	result, err := s.authenticate(ctx, req.Email, req.Password)
+	if err != nil {
+		return nil, s.convertLoginError(err)
+	}
+
+	tokenType := result.TokenType
+	if tokenType == "" {
+		tokenType = DefaultTokenType
+	}
+
+	resp := &pb.LoginResponse{
+		AccessToken: result.Token,
+		TokenType:   tokenType,
+		ExpiresAt:   result.ExpiresAt,
+		User:        s.converter.ConvertUserToProto(result.User),
+	}
+
+	if req.IssueRefreshToken {
+		if result.User == nil {
+			return nil, status.Error(codes.Internal, "login did not report the authenticated user")
+		}
+		refreshToken, session, err := s.startSession(ctx, result.User.ID, result.Tenant, req.Device)
+		if err != nil {
+			return nil, s.convertError(err)
+		}
+		resp.RefreshToken = refreshToken
+		resp.RefreshTokenExpiresAt = timestamppb.New(session.ExpiresAt)
+		resp.SessionId = session.ID
+	}
+
+	return resp, nil
+}
+
+// authenticate delegates to the adapter's Authenticator when available and
+// otherwise combines Login with a lookup of the authenticated user.
+// Soft-deleted users cannot log in.
+func (s *UserServiceServer) authenticate(ctx context.Context, email, password string) (*models.LoginResult, error) {
+	result, err := s.authenticateWithAdapter(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil && result.User != nil && result.User.DeletedAt != nil {
+		return nil, fmt.Errorf("user is deleted: %w", models.ErrInvalidCredentials)
+	}
+	return result, nil
+}
+
+func (s *UserServiceServer) authenticateWithAdapter(ctx context.Context, email, password string) (*models.LoginResult, error) {
+	if authenticator, ok := s.userService.(Authenticator); ok {
+		return authenticator.Authenticate(ctx, email, password)
+	}
+
+	token, err := s.userService.Login(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResult{
+		Token: token,
+		User:  user,
+	}, nil
+}
+
+// convertLoginError hides whether the email or the password was wrong so
+// that Login cannot be used to enumerate accounts
+func (s *UserServiceServer) convertLoginError(err error) error {
+	converted := s.convertError(err)
+	switch status.Code(converted) {
+	case codes.NotFound, codes.Unauthenticated:
+		return newStatusError(codes.Unauthenticated, "invalid credentials", models.ReasonInvalidCredentials, nil, nil)
 	default:
-		return status.Error(codes.Internal, fmt.Sprintf("internal error: %v", err))
+		return converted
 	}
 }
 
-// Helper function to check if a string contains a substring
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || (len(s) > len(substr) &&
-		(s[:len(substr)] == substr || s[len(s)-len(substr):] == substr ||
-			containsInside(s, substr))))
+// convertPasswordError converts an UpdatePassword failure to a gRPC status
+// without ever echoing either password back to the caller
+func (s *UserServiceServer) convertPasswordError(err error, input models.UserPasswordUpdateInput) error {
+	st := status.Convert(s.convertError(err))
+	if st.Code() == codes.Unauthenticated {
+		return newStatusError(codes.Unauthenticated, "current password is incorrect", models.ReasonInvalidCredentials, nil, nil)
+	}
+
+	// Rewrite the message and every string in the details, such as field
+	// violation descriptions and ErrorInfo metadata, so that the details
+	// are preserved without the passwords
+	secrets := []string{input.CurrentPassword, input.NewPassword}
+	redacted := st.Proto()
+	redacted.Message = redactSecrets(redacted.Message, secrets)
+	details := redacted.Details[:0]
+	for _, detail := range redacted.Details {
+		msg, err := detail.UnmarshalNew()
+		if err != nil {
+			// Details that cannot be inspected cannot be redacted either
+			continue
+		}
+		redactMessage(msg.ProtoReflect(), secrets)
+		if detail, err = anypb.New(msg); err == nil {
+			details = append(details, detail)
+		}
+	}
+	redacted.Details = details
+	return status.ErrorProto(redacted)
 }
 
-func containsInside(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
+// redactSecrets replaces every non-empty secret in text
+func redactSecrets(text string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, "[REDACTED]")
 		}
 	}
-	return false
+	return text
+}
+
+// redactMessage applies redactSecrets to every string field of msg, in
+// nested messages, lists and map values included
+func redactMessage(msg protoreflect.Message, secrets []string) {
+	msg.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case field.IsList():
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				if redactedValue, ok := redactValue(field, list.Get(i), secrets); ok {
+					list.Set(i, redactedValue)
+				}
+			}
+		case field.IsMap():
+			m := value.Map()
+			m.Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+				if redactedValue, ok := redactValue(field.MapValue(), value, secrets); ok {
+					m.Set(key, redactedValue)
+				}
+				return true
+			})
+		default:
+			if redactedValue, ok := redactValue(field, value, secrets); ok {
+				msg.Set(field, redactedValue)
+			}
+		}
+		return true
+	})
+}
+
+// redactValue redacts a single string or message value and reports whether
+// it has to be stored back
+func redactValue(field protoreflect.FieldDescriptor, value protoreflect.Value, secrets []string) (protoreflect.Value, bool) {
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(redactSecrets(value.String(), secrets)), true
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		redactMessage(value.Message(), secrets)
+	}
+	return value, false
+}
+
+// isSpecifiedRole reports whether role is a known, non-default enum value
+func isSpecifiedRole(role pb.Role) bool {
+	if role == pb.Role_ROLE_UNSPECIFIED {
+		return false
+	}
+	_, ok := pb.Role_name[int32(role)]
+	return ok
 }
@@ -3,14 +3,21 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
 	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
 )
 
@@ -19,49 +26,49 @@ type MockUserService struct {
 	mock.Mock
 }
 
-func (m *MockUserService) CreateUser(ctx context.Context, input UserCreateInput) (*UserModel, error) {
+func (m *MockUserService) CreateUser(ctx context.Context, input models.UserCreateInput) (*models.UserModel, error) {
 	args := m.Called(ctx, input)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*UserModel), args.Error(1)
+	return args.Get(0).(*models.UserModel), args.Error(1)
 }
 
-func (m *MockUserService) GetUserByID(ctx context.Context, id string) (*UserModel, error) {
+func (m *MockUserService) GetUserByID(ctx context.Context, id string) (*models.UserModel, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*UserModel), args.Error(1)
+	return args.Get(0).(*models.UserModel), args.Error(1)
 }
 
-func (m *MockUserService) GetUserByEmail(ctx context.Context, email string) (*UserModel, error) {
+func (m *MockUserService) GetUserByEmail(ctx context.Context, email string) (*models.UserModel, error) {
 	args := m.Called(ctx, email)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*UserModel), args.Error(1)
+	return args.Get(0).(*models.UserModel), args.Error(1)
 }
 
-func (m *MockUserService) UpdateUser(ctx context.Context, id string, input UserUpdateInput) (*UserModel, error) {
+func (m *MockUserService) UpdateUser(ctx context.Context, id string, input models.UserUpdateInput) (*models.UserModel, error) {
 	args := m.Called(ctx, id, input)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*UserModel), args.Error(1)
+	return args.Get(0).(*models.UserModel), args.Error(1)
 }
 
-func (m *MockUserService) DeleteUser(ctx context.Context, id string, actorID string, actorRole Role) error {
+func (m *MockUserService) DeleteUser(ctx context.Context, id string, actorID string, actorRole models.Role) error {
 	args := m.Called(ctx, id, actorID, actorRole)
 	return args.Error(0)
 }
 
-func (m *MockUserService) ListUsers(ctx context.Context, page, pageSize int64) (*PaginatedUsersModel, error) {
+func (m *MockUserService) ListUsers(ctx context.Context, page, pageSize int64) (*models.PaginatedUsersModel, error) {
 	args := m.Called(ctx, page, pageSize)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*PaginatedUsersModel), args.Error(1)
+	return args.Get(0).(*models.PaginatedUsersModel), args.Error(1)
 }
 
 func (m *MockUserService) Login(ctx context.Context, email, password string) (string, error) {
@@ -69,16 +76,27 @@ func (m *MockUserService) Login(ctx context.Context, email, password string) (st
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockUserService) UpdateUserRole(ctx context.Context, id string, role Role, actorRole Role) error {
+func (m *MockUserService) UpdateUserRole(ctx context.Context, id string, role models.Role, actorRole models.Role) error {
 	args := m.Called(ctx, id, role, actorRole)
 	return args.Error(0)
 }
 
-func (m *MockUserService) UpdatePassword(ctx context.Context, id string, input UserPasswordUpdateInput) error {
+func (m *MockUserService) UpdatePassword(ctx context.Context, id string, input models.UserPasswordUpdateInput) error {
 	args := m.Called(ctx, id, input)
 	return args.Error(0)
 }
 
+// newTestServer creates a server with valid options
+func newTestServer(t *testing.T, userService UserServiceInterface, opts ...Option) *UserServiceServer {
+	t.Helper()
+
+	server, err := NewUserServiceServer(userService, opts...)
+	if err != nil {
+		t.Fatalf("NewUserServiceServer: %v", err)
+	}
+	return server
+}
+
 func TestUserServiceServer_CreateUser(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -96,17 +114,17 @@ func TestUserServiceServer_CreateUser(t *testing.T) {
 				LastName:  "Doe",
 			},
 			mockSetup: func(m *MockUserService) {
-				m.On("CreateUser", mock.Anything, UserCreateInput{
+				m.On("CreateUser", mock.Anything, models.UserCreateInput{
 					Email:     "test@example.com",
 					Password:  "password123",
 					FirstName: "John",
 					LastName:  "Doe",
-				}).Return(&UserModel{
+				}).Return(&models.UserModel{
 					ID:        "123",
 					Email:     "test@example.com",
 					FirstName: "John",
 					LastName:  "Doe",
-					Role:      RoleUser,
+					Role:      models.RoleUser,
 					CreatedAt: timestamppb.Now(),
 					UpdatedAt: timestamppb.Now(),
 				}, nil)
@@ -143,7 +161,7 @@ func TestUserServiceServer_CreateUser(t *testing.T) {
 			mockService := &MockUserService{}
 			tt.mockSetup(mockService)
 
-			server := NewUserServiceServer(mockService)
+			server := newTestServer(t, mockService)
 
 			resp, err := server.CreateUser(context.Background(), tt.request)
 
@@ -178,12 +196,12 @@ func TestUserServiceServer_GetUserByID(t *testing.T) {
 				Id: "123",
 			},
 			mockSetup: func(m *MockUserService) {
-				m.On("GetUserByID", mock.Anything, "123").Return(&UserModel{
+				m.On("GetUserByID", mock.Anything, "123").Return(&models.UserModel{
 					ID:        "123",
 					Email:     "test@example.com",
 					FirstName: "John",
 					LastName:  "Doe",
-					Role:      RoleUser,
+					Role:      models.RoleUser,
 				}, nil)
 			},
 			expectedResult: true,
@@ -206,6 +224,27 @@ func TestUserServiceServer_GetUserByID(t *testing.T) {
 			},
 			expectedError: codes.NotFound,
 		},
+		{
+			name: "soft-deleted user",
+			request: &pb.GetUserByIDRequest{
+				Id: "123",
+			},
+			mockSetup: func(m *MockUserService) {
+				m.On("GetUserByID", mock.Anything, "123").Return(&models.UserModel{ID: "123", DeletedAt: timestamppb.Now()}, nil)
+			},
+			expectedError: codes.NotFound,
+		},
+		{
+			name: "soft-deleted user explicitly requested",
+			request: &pb.GetUserByIDRequest{
+				Id:             "123",
+				IncludeDeleted: true,
+			},
+			mockSetup: func(m *MockUserService) {
+				m.On("GetUserByID", mock.Anything, "123").Return(&models.UserModel{ID: "123", DeletedAt: timestamppb.Now()}, nil)
+			},
+			expectedResult: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -213,7 +252,7 @@ func TestUserServiceServer_GetUserByID(t *testing.T) {
 			mockService := &MockUserService{}
 			tt.mockSetup(mockService)
 
-			server := NewUserServiceServer(mockService)
+			server := newTestServer(t, mockService)
 
 			resp, err := server.GetUserByID(context.Background(), tt.request)
 
@@ -234,17 +273,40 @@ func TestUserServiceServer_GetUserByID(t *testing.T) {
 	}
 }
 
+func TestUserServiceServer_GetUserByEmail_SoftDeleted(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("GetUserByEmail", mock.Anything, "test@example.com").Return(&models.UserModel{ID: "123", DeletedAt: timestamppb.Now()}, nil)
+	server := newTestServer(t, mockService)
+
+	_, err := server.GetUserByEmail(context.Background(), &pb.GetUserByEmailRequest{Email: "test@example.com"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	resp, err := server.GetUserByEmail(context.Background(), &pb.GetUserByEmailRequest{Email: "test@example.com", IncludeDeleted: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "123", resp.GetUser().GetId())
+}
+
+func TestUserServiceServer_Login_SoftDeleted(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("Login", mock.Anything, "test@example.com", "password123").Return("token-123", nil)
+	mockService.On("GetUserByEmail", mock.Anything, "test@example.com").Return(&models.UserModel{ID: "123", DeletedAt: timestamppb.Now()}, nil)
+	server := newTestServer(t, mockService)
+
+	_, err := server.Login(context.Background(), &pb.LoginRequest{Email: "test@example.com", Password: "password123"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
 func TestModelConverter_ConvertRoleToProto(t *testing.T) {
 	converter := NewModelConverter()
 
 	tests := []struct {
-		domainRole Role
+		domainRole models.Role
 		protoRole  pb.Role
 	}{
-		{RoleUser, pb.Role_ROLE_USER},
-		{RoleModerator, pb.Role_ROLE_MODERATOR},
-		{RoleAdmin, pb.Role_ROLE_ADMIN},
-		{Role("invalid"), pb.Role_ROLE_UNSPECIFIED},
+		{models.RoleUser, pb.Role_ROLE_USER},
+		{models.RoleModerator, pb.Role_ROLE_MODERATOR},
+		{models.RoleAdmin, pb.Role_ROLE_ADMIN},
+		{models.Role("invalid"), pb.Role_ROLE_UNSPECIFIED},
 	}
 
 	for _, tt := range tests {
@@ -258,12 +320,12 @@ func TestModelConverter_ConvertRoleFromProto(t *testing.T) {
 
 	tests := []struct {
 		protoRole  pb.Role
-		domainRole Role
+		domainRole models.Role
 	}{
-		{pb.Role_ROLE_USER, RoleUser},
-		{pb.Role_ROLE_MODERATOR, RoleModerator},
-		{pb.Role_ROLE_ADMIN, RoleAdmin},
-		{pb.Role_ROLE_UNSPECIFIED, RoleUser}, // Default fallback
+		{pb.Role_ROLE_USER, models.RoleUser},
+		{pb.Role_ROLE_MODERATOR, models.RoleModerator},
+		{pb.Role_ROLE_ADMIN, models.RoleAdmin},
+		{pb.Role_ROLE_UNSPECIFIED, models.RoleUser}, // Default fallback
 	}
 
 	for _, tt := range tests {
@@ -271,3 +333,1013 @@ func TestModelConverter_ConvertRoleFromProto(t *testing.T) {
 		assert.Equal(t, tt.domainRole, result)
 	}
 }
+
+// MockAuthenticatingUserService additionally implements Authenticator
+type MockAuthenticatingUserService struct {
+	MockUserService
+}
+
+func (m *MockAuthenticatingUserService) Authenticate(ctx context.Context, email, password string) (*models.LoginResult, error) {
+	args := m.Called(ctx, email, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginResult), args.Error(1)
+}
+
+func TestUserServiceServer_Login(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.LoginRequest
+		mockSetup     func(*MockUserService)
+		expectedError codes.Code
+		expectedToken string
+	}{
+		{
+			name: "successful login",
+			request: &pb.LoginRequest{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			mockSetup: func(m *MockUserService) {
+				m.On("Login", mock.Anything, "test@example.com", "password123").Return("token-123", nil)
+				m.On("GetUserByEmail", mock.Anything, "test@example.com").Return(&models.UserModel{
+					ID:    "123",
+					Email: "test@example.com",
+					Role:  models.RoleUser,
+				}, nil)
+			},
+			expectedToken: "token-123",
+		},
+		{
+			name: "missing password",
+			request: &pb.LoginRequest{
+				Email: "test@example.com",
+			},
+			mockSetup:     func(m *MockUserService) {},
+			expectedError: codes.InvalidArgument,
+		},
+		{
+			name: "invalid credentials",
+			request: &pb.LoginRequest{
+				Email:    "test@example.com",
+				Password: "wrong",
+			},
+			mockSetup: func(m *MockUserService) {
+				m.On("Login", mock.Anything, "test@example.com", "wrong").Return("", errors.New("invalid credentials"))
+			},
+			expectedError: codes.Unauthenticated,
+		},
+		{
+			name: "unknown email",
+			request: &pb.LoginRequest{
+				Email:    "missing@example.com",
+				Password: "password123",
+			},
+			mockSetup: func(m *MockUserService) {
+				m.On("Login", mock.Anything, "missing@example.com", "password123").Return("", errors.New("user not found"))
+			},
+			expectedError: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserService{}
+			tt.mockSetup(mockService)
+
+			server := newTestServer(t, mockService)
+
+			resp, err := server.Login(context.Background(), tt.request)
+
+			if tt.expectedError != codes.OK {
+				assert.Error(t, err)
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedError, st.Code())
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, resp)
+				assert.Equal(t, tt.expectedToken, resp.AccessToken)
+				assert.Equal(t, DefaultTokenType, resp.TokenType)
+				assert.Equal(t, tt.request.Email, resp.User.Email)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServiceServer_Login_Authenticator(t *testing.T) {
+	mockService := &MockAuthenticatingUserService{}
+	expiresAt := timestamppb.Now()
+	mockService.On("Authenticate", mock.Anything, "test@example.com", "password123").Return(&models.LoginResult{
+		Token:     "jwt",
+		TokenType: "JWT",
+		ExpiresAt: expiresAt,
+		User:      &models.UserModel{ID: "123", Email: "test@example.com"},
+	}, nil)
+
+	server := newTestServer(t, mockService)
+
+	resp, err := server.Login(context.Background(), &pb.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt", resp.AccessToken)
+	assert.Equal(t, "JWT", resp.TokenType)
+	assert.Equal(t, expiresAt, resp.ExpiresAt)
+	assert.Equal(t, "123", resp.User.Id)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_UpdateUserRole(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.UpdateUserRoleRequest
+		mockSetup     func(*MockUserService)
+		expectedError codes.Code
+	}{
+		{
+			name: "admin promotes moderator",
+			request: &pb.UpdateUserRoleRequest{
+				Id:        "123",
+				Role:      pb.Role_ROLE_MODERATOR,
+				ActorRole: pb.Role_ROLE_ADMIN,
+			},
+			mockSetup: func(m *MockUserService) {
+				m.On("UpdateUserRole", mock.Anything, "123", models.RoleModerator, models.RoleAdmin).Return(nil)
+			},
+		},
+		{
+			name: "unspecified role",
+			request: &pb.UpdateUserRoleRequest{
+				Id:        "123",
+				ActorRole: pb.Role_ROLE_ADMIN,
+			},
+			mockSetup:     func(m *MockUserService) {},
+			expectedError: codes.InvalidArgument,
+		},
+		{
+			name: "unspecified actor role",
+			request: &pb.UpdateUserRoleRequest{
+				Id:   "123",
+				Role: pb.Role_ROLE_MODERATOR,
+			},
+			mockSetup:     func(m *MockUserService) {},
+			expectedError: codes.InvalidArgument,
+		},
+		{
+			name: "unknown role value",
+			request: &pb.UpdateUserRoleRequest{
+				Id:        "123",
+				Role:      pb.Role(42),
+				ActorRole: pb.Role_ROLE_ADMIN,
+			},
+			mockSetup:     func(m *MockUserService) {},
+			expectedError: codes.InvalidArgument,
+		},
+		{
+			name: "insufficient rights",
+			request: &pb.UpdateUserRoleRequest{
+				Id:        "123",
+				Role:      pb.Role_ROLE_ADMIN,
+				ActorRole: pb.Role_ROLE_USER,
+			},
+			mockSetup: func(m *MockUserService) {
+				m.On("UpdateUserRole", mock.Anything, "123", models.RoleAdmin, models.RoleUser).Return(errors.New("insufficient rights"))
+			},
+			expectedError: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserService{}
+			tt.mockSetup(mockService)
+
+			server := newTestServer(t, mockService)
+
+			resp, err := server.UpdateUserRole(context.Background(), tt.request)
+
+			if tt.expectedError != codes.OK {
+				assert.Error(t, err)
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedError, st.Code())
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, resp.Success)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserServiceServer_UpdatePassword(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *pb.UpdatePasswordRequest
+		mockSetup     func(*MockUserService)
+		expectedError codes.Code
+	}{
+		{
+			name: "successful password change",
+			request: &pb.UpdatePasswordRequest{
+				Id:              "123",
+				CurrentPassword: "old-secret",
+				NewPassword:     "new-secret",
+			},
+			mockSetup: func(m *MockUserService) {
+				m.On("UpdatePassword", mock.Anything, "123", models.UserPasswordUpdateInput{
+					CurrentPassword: "old-secret",
+					NewPassword:     "new-secret",
+				}).Return(nil)
+			},
+		},
+		{
+			name: "missing new password",
+			request: &pb.UpdatePasswordRequest{
+				Id:              "123",
+				CurrentPassword: "old-secret",
+			},
+			mockSetup:     func(m *MockUserService) {},
+			expectedError: codes.InvalidArgument,
+		},
+		{
+			name: "unchanged password",
+			request: &pb.UpdatePasswordRequest{
+				Id:              "123",
+				CurrentPassword: "old-secret",
+				NewPassword:     "old-secret",
+			},
+			mockSetup:     func(m *MockUserService) {},
+			expectedError: codes.InvalidArgument,
+		},
+		{
+			name: "wrong current password",
+			request: &pb.UpdatePasswordRequest{
+				Id:              "123",
+				CurrentPassword: "guess",
+				NewPassword:     "new-secret",
+			},
+			mockSetup: func(m *MockUserService) {
+				m.On("UpdatePassword", mock.Anything, "123", mock.Anything).Return(errors.New("invalid credentials: guess"))
+			},
+			expectedError: codes.Unauthenticated,
+		},
+		{
+			name: "adapter error mentioning password",
+			request: &pb.UpdatePasswordRequest{
+				Id:              "123",
+				CurrentPassword: "old-secret",
+				NewPassword:     "new-secret",
+			},
+			mockSetup: func(m *MockUserService) {
+				m.On("UpdatePassword", mock.Anything, "123", mock.Anything).Return(errors.New("hash new-secret: failed"))
+			},
+			expectedError: codes.Internal,
+		},
+		{
+			name: "validation error mentioning password",
+			request: &pb.UpdatePasswordRequest{
+				Id:              "123",
+				CurrentPassword: "old-secret",
+				NewPassword:     "new-secret",
+			},
+			mockSetup: func(m *MockUserService) {
+				m.On("UpdatePassword", mock.Anything, "123", mock.Anything).Return(models.NewValidationError("new_password", "new-secret is too common"))
+			},
+			expectedError: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserService{}
+			tt.mockSetup(mockService)
+
+			server := newTestServer(t, mockService)
+
+			resp, err := server.UpdatePassword(context.Background(), tt.request)
+
+			if tt.expectedError != codes.OK {
+				assert.Error(t, err)
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedError, st.Code())
+				assert.NotContains(t, st.Message(), "secret")
+				assert.NotContains(t, st.Message(), "guess")
+				for _, detail := range st.Details() {
+					assert.NotContains(t, fmt.Sprint(detail), "secret")
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, resp.Success)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedactMessage(t *testing.T) {
+	info := &errdetails.ErrorInfo{
+		Reason:   "new-secret rejected",
+		Metadata: map[string]string{"hint": "do not reuse new-secret", "field": "new_password"},
+	}
+	redactMessage(info.ProtoReflect(), []string{"", "new-secret"})
+
+	assert.Equal(t, "[REDACTED] rejected", info.Reason)
+	assert.Equal(t, map[string]string{"hint": "do not reuse [REDACTED]", "field": "new_password"}, info.Metadata)
+}
+
+func TestUserServiceServer_ConvertError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedCode   codes.Code
+		expectedReason string
+		expectedFields []string
+		// expectedMessage is checked when set
+		expectedMessage string
+	}{
+		{
+			name:            "wrapped not found",
+			err:             fmt.Errorf("user 123: %w", models.ErrNotFound),
+			expectedCode:    codes.NotFound,
+			expectedReason:  models.ReasonNotFound,
+			expectedMessage: "not found",
+		},
+		{
+			name:           "typed error is not misclassified by its message",
+			err:            fmt.Errorf("validation service not found: %w", models.ErrAlreadyExists),
+			expectedCode:   codes.AlreadyExists,
+			expectedReason: models.ReasonAlreadyExists,
+		},
+		{
+			name:            "wrapped adapter details are not exposed",
+			err:             fmt.Errorf(`pq: duplicate key value violates unique constraint "users_email_key": %w`, models.ErrAlreadyExists),
+			expectedCode:    codes.AlreadyExists,
+			expectedReason:  models.ReasonAlreadyExists,
+			expectedMessage: "already exists",
+		},
+		{
+			name:           "insufficient rights",
+			err:            models.ErrInsufficientRights,
+			expectedCode:   codes.PermissionDenied,
+			expectedReason: models.ReasonInsufficientRights,
+		},
+		{
+			name: "joined validation errors",
+			err: errors.Join(
+				models.NewValidationError("email", "must be a valid address"),
+				models.NewValidationError("first_name", "must not be empty"),
+			),
+			expectedCode:    codes.InvalidArgument,
+			expectedReason:  models.ReasonValidationFailed,
+			expectedFields:  []string{"email", "first_name"},
+			expectedMessage: "validation failed",
+		},
+		{
+			name:         "legacy message fallback",
+			err:          errors.New("user not found"),
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "unknown error",
+			err:          errors.New("pq: connection refused"),
+			expectedCode: codes.Internal,
+		},
+	}
+
+	server := newTestServer(t, &MockUserService{})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := status.FromError(server.convertError(tt.err))
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedCode, st.Code())
+			if tt.expectedMessage != "" {
+				assert.Equal(t, tt.expectedMessage, st.Message())
+			}
+
+			var reason string
+			var fields []string
+			for _, detail := range st.Details() {
+				switch d := detail.(type) {
+				case *errdetails.ErrorInfo:
+					reason = d.Reason
+					assert.Equal(t, models.ErrorDomain, d.Domain)
+				case *errdetails.BadRequest:
+					for _, v := range d.FieldViolations {
+						fields = append(fields, v.Field)
+					}
+				}
+			}
+			assert.Equal(t, tt.expectedReason, reason)
+			assert.Equal(t, tt.expectedFields, fields)
+		})
+	}
+}
+
+// MockCursorUserService additionally implements CursorLister
+type MockCursorUserService struct {
+	MockUserService
+}
+
+func (m *MockCursorUserService) ListUsersCursor(ctx context.Context, query models.UserQuery, cursor string, pageSize int64) (*models.UserCursorPageModel, error) {
+	args := m.Called(ctx, query, cursor, pageSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserCursorPageModel), args.Error(1)
+}
+
+func TestUserServiceServer_GetUsers_Offset(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("ListUsers", mock.Anything, int64(1), int64(10)).Return(&models.PaginatedUsersModel{
+		Users:      []*models.UserModel{{ID: "1"}},
+		Total:      15,
+		Page:       1,
+		PageSize:   10,
+		TotalPages: 2,
+	}, nil)
+	mockService.On("ListUsers", mock.Anything, int64(2), int64(10)).Return(&models.PaginatedUsersModel{
+		Users:      []*models.UserModel{{ID: "11"}},
+		Total:      15,
+		Page:       2,
+		PageSize:   10,
+		TotalPages: 2,
+	}, nil)
+
+	server := newTestServer(t, mockService)
+
+	first, err := server.GetUsers(context.Background(), &pb.GetUsersRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", first.Users[0].Id)
+	assert.NotEmpty(t, first.NextPageToken)
+
+	second, err := server.GetUsers(context.Background(), &pb.GetUsersRequest{PageToken: first.NextPageToken})
+	assert.NoError(t, err)
+	assert.Equal(t, "11", second.Users[0].Id)
+	assert.Empty(t, second.NextPageToken)
+
+	legacy, err := server.GetUsers(context.Background(), &pb.GetUsersRequest{Page: 2, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), legacy.Page)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_GetUsers_SkipsDeleted(t *testing.T) {
+	users := []*models.UserModel{{ID: "1"}, {ID: "2", DeletedAt: timestamppb.Now()}, {ID: "3"}}
+
+	legacy := &MockUserService{}
+	legacy.On("ListUsers", mock.Anything, int64(1), int64(10)).Return(&models.PaginatedUsersModel{
+		Users: users, Total: 3, Page: 1, PageSize: 10, TotalPages: 1,
+	}, nil)
+	resp, err := newTestServer(t, legacy).GetUsers(context.Background(), &pb.GetUsersRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, userIDs(resp.Users))
+
+	cursor := &MockCursorUserService{}
+	cursor.On("ListUsersCursor", mock.Anything, models.UserQuery{}, "", int64(10)).Return(&models.UserCursorPageModel{Users: users}, nil)
+	resp, err = newTestServer(t, cursor).GetUsers(context.Background(), &pb.GetUsersRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, userIDs(resp.Users))
+
+	filter := models.UserQuery{Filter: models.UserFilter{IncludeDeleted: true}}
+	cursor.On("ListUsersCursor", mock.Anything, filter, "", int64(10)).Return(&models.UserCursorPageModel{Users: users}, nil)
+	resp, err = newTestServer(t, cursor).GetUsers(context.Background(), &pb.GetUsersRequest{Filter: &pb.UserFilter{IncludeDeleted: true}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, userIDs(resp.Users))
+}
+
+func userIDs(users []*pb.User) []string {
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.Id
+	}
+	return ids
+}
+
+func TestUserServiceServer_GetUsers_Cursor(t *testing.T) {
+	mockService := &MockCursorUserService{}
+	mockService.On("ListUsersCursor", mock.Anything, models.UserQuery{}, "", int64(DefaultMaxPageSize)).Return(&models.UserCursorPageModel{
+		Users:      []*models.UserModel{{ID: "1"}},
+		NextCursor: "created_at>2024-01-01,id>1",
+	}, nil)
+	mockService.On("ListUsersCursor", mock.Anything, models.UserQuery{}, "created_at>2024-01-01,id>1", int64(DefaultMaxPageSize)).Return(&models.UserCursorPageModel{
+		Users: []*models.UserModel{{ID: "2"}},
+	}, nil)
+
+	server := newTestServer(t, mockService)
+
+	first, err := server.GetUsers(context.Background(), &pb.GetUsersRequest{PageSize: 10000})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultMaxPageSize, first.PageSize)
+	assert.NotEmpty(t, first.NextPageToken)
+	assert.NotContains(t, first.NextPageToken, "created_at")
+
+	second, err := server.GetUsers(context.Background(), &pb.GetUsersRequest{PageSize: 10000, PageToken: first.NextPageToken})
+	assert.NoError(t, err)
+	assert.Equal(t, "2", second.Users[0].Id)
+	assert.Empty(t, second.NextPageToken)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_GetUsers_RejectsForgedToken(t *testing.T) {
+	mockService := &MockCursorUserService{}
+
+	issuer := newTestServer(t, mockService, WithPageTokenKey([]byte("other-key-at-least-32-bytes-long")))
+	forged, err := issuer.pageTokens.encode(pageToken{Cursor: "id>0"})
+	assert.NoError(t, err)
+
+	server := newTestServer(t, mockService, WithPageTokenKey([]byte("server-key-at-least-32-bytes-long")))
+
+	for _, token := range []string{forged, "not-a-token", "e30.AAAA"} {
+		_, err := server.GetUsers(context.Background(), &pb.GetUsersRequest{PageToken: token})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestWithPageTokenKey_RejectsShortKey(t *testing.T) {
+	for _, key := range [][]byte{nil, []byte("short")} {
+		server, err := NewUserServiceServer(&MockUserService{}, WithPageTokenKey(key))
+		assert.Error(t, err)
+		assert.Nil(t, server)
+	}
+
+	server, err := NewUserServiceServer(&MockUserService{}, WithPageTokenKey(make([]byte, MinPageTokenKeySize)))
+	assert.NoError(t, err)
+	assert.NotNil(t, server)
+}
+
+// MockFilteringUserService additionally implements FilteredLister
+type MockFilteringUserService struct {
+	MockUserService
+}
+
+func (m *MockFilteringUserService) ListUsersFiltered(ctx context.Context, query models.UserQuery, page, pageSize int64) (*models.PaginatedUsersModel, error) {
+	args := m.Called(ctx, query, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PaginatedUsersModel), args.Error(1)
+}
+
+func TestUserServiceServer_GetUsers_Filter(t *testing.T) {
+	weekAgo := timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	minRating := int32(10)
+
+	mockService := &MockFilteringUserService{}
+	mockService.On("ListUsersFiltered", mock.Anything, models.UserQuery{
+		Filter: models.UserFilter{
+			Roles:        []models.Role{models.RoleModerator},
+			CreatedAfter: weekAgo,
+			MinRating:    &minRating,
+		},
+		OrderBy: []models.OrderBy{
+			{Field: models.OrderFieldRating, Descending: true},
+			{Field: models.OrderFieldCreatedAt},
+		},
+	}, int64(1), int64(10)).Return(&models.PaginatedUsersModel{
+		Users:      []*models.UserModel{{ID: "1", Role: models.RoleModerator}},
+		Total:      11,
+		Page:       1,
+		PageSize:   10,
+		TotalPages: 2,
+	}, nil)
+
+	server := newTestServer(t, mockService)
+
+	req := &pb.GetUsersRequest{
+		Filter: &pb.UserFilter{
+			Roles:        []pb.Role{pb.Role_ROLE_MODERATOR},
+			CreatedAfter: weekAgo,
+			MinRating:    &minRating,
+		},
+		OrderBy: "rating desc, created_at",
+	}
+	resp, err := server.GetUsers(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", resp.Users[0].Id)
+
+	// The page token is bound to the filter it was issued for
+	_, err = server.GetUsers(context.Background(), &pb.GetUsersRequest{
+		PageToken: resp.NextPageToken,
+		OrderBy:   "rating desc, created_at",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_GetUsers_InvalidQuery(t *testing.T) {
+	minRating, maxRating := int32(50), int32(10)
+
+	tests := []struct {
+		name          string
+		service       UserServiceInterface
+		request       *pb.GetUsersRequest
+		expectedField string
+	}{
+		{
+			name:          "unsupported order_by field",
+			service:       &MockFilteringUserService{},
+			request:       &pb.GetUsersRequest{OrderBy: "password desc"},
+			expectedField: "order_by",
+		},
+		{
+			name:          "bad order_by direction",
+			service:       &MockFilteringUserService{},
+			request:       &pb.GetUsersRequest{OrderBy: "rating sideways"},
+			expectedField: "order_by",
+		},
+		{
+			name:    "inverted rating range",
+			service: &MockFilteringUserService{},
+			request: &pb.GetUsersRequest{Filter: &pb.UserFilter{
+				MinRating: &minRating,
+				MaxRating: &maxRating,
+			}},
+			expectedField: "filter.min_rating",
+		},
+		{
+			name:    "unspecified role",
+			service: &MockFilteringUserService{},
+			request: &pb.GetUsersRequest{Filter: &pb.UserFilter{
+				Roles: []pb.Role{pb.Role_ROLE_UNSPECIFIED},
+			}},
+			expectedField: "filter.roles",
+		},
+		{
+			name:    "adapter without filter support",
+			service: &MockUserService{},
+			request: &pb.GetUsersRequest{Filter: &pb.UserFilter{
+				EmailDomain: "example.com",
+			}},
+			expectedField: "filter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.service)
+
+			_, err := server.GetUsers(context.Background(), tt.request)
+
+			st := status.Convert(err)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+
+			var fields []string
+			for _, detail := range st.Details() {
+				if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+					for _, v := range badRequest.FieldViolations {
+						fields = append(fields, v.Field)
+					}
+				}
+			}
+			assert.Equal(t, []string{tt.expectedField}, fields)
+		})
+	}
+}
+
+// MockSearchingUserService additionally implements Searcher
+type MockSearchingUserService struct {
+	MockUserService
+}
+
+func (m *MockSearchingUserService) SearchUsers(ctx context.Context, query string, cursor string, pageSize int64) (*models.UserSearchPageModel, error) {
+	args := m.Called(ctx, query, cursor, pageSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSearchPageModel), args.Error(1)
+}
+
+func searchFixtureUsers() []*models.UserModel {
+	return []*models.UserModel{
+		{ID: "1", Email: "john.doe@example.com", FirstName: "John", LastName: "Doe"},
+		{ID: "2", Email: "johnny@example.com", FirstName: "Johnny", LastName: "Walker"},
+		{ID: "3", Email: "jose@example.com", FirstName: "José", LastName: "Álvarez"},
+		{ID: "4", Email: "jane@example.com", FirstName: "Jane", LastName: "Roe"},
+		{ID: "5", Email: "old@example.com", FirstName: "John", LastName: "Deleted", DeletedAt: timestamppb.Now()},
+	}
+}
+
+func TestUserServiceServer_SearchUsers_InMemory(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		expectedIDs []string
+	}{
+		{name: "exact match ranks above prefix", query: "john", expectedIDs: []string{"1", "2"}},
+		{name: "prefix", query: "wal", expectedIDs: []string{"2"}},
+		{name: "typo tolerant", query: "jhon doe", expectedIDs: []string{"1"}},
+		{name: "unicode normalized", query: "ALVAREZ", expectedIDs: []string{"3"}},
+		{name: "email domain", query: "jane@example", expectedIDs: []string{"4"}},
+		{name: "no match", query: "zzzz", expectedIDs: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockUserService{}
+			mockService.On("ListUsers", mock.Anything, int64(1), mock.Anything).Return(&models.PaginatedUsersModel{
+				Users:      searchFixtureUsers(),
+				Total:      5,
+				Page:       1,
+				TotalPages: 1,
+			}, nil)
+
+			server := newTestServer(t, mockService)
+
+			resp, err := server.SearchUsers(context.Background(), &pb.SearchUsersRequest{Query: tt.query})
+			assert.NoError(t, err)
+
+			ids := []string{}
+			for _, r := range resp.Results {
+				ids = append(ids, r.User.Id)
+				assert.Greater(t, r.Score, 0.0)
+				assert.LessOrEqual(t, r.Score, 1.0)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
+
+func TestUserServiceServer_SearchUsers_InMemoryPagination(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("ListUsers", mock.Anything, int64(1), mock.Anything).Return(&models.PaginatedUsersModel{
+		Users:      searchFixtureUsers(),
+		TotalPages: 1,
+	}, nil)
+
+	server := newTestServer(t, mockService)
+
+	first, err := server.SearchUsers(context.Background(), &pb.SearchUsersRequest{Query: "john", PageSize: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", first.Results[0].User.Id)
+	assert.NotEmpty(t, first.NextPageToken)
+
+	second, err := server.SearchUsers(context.Background(), &pb.SearchUsersRequest{Query: "john", PageSize: 1, PageToken: first.NextPageToken})
+	assert.NoError(t, err)
+	assert.Equal(t, "2", second.Results[0].User.Id)
+	assert.Empty(t, second.NextPageToken)
+
+	_, err = server.SearchUsers(context.Background(), &pb.SearchUsersRequest{Query: "jane", PageToken: first.NextPageToken})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestUserServiceServer_SearchUsers_Searcher(t *testing.T) {
+	mockService := &MockSearchingUserService{}
+	mockService.On("SearchUsers", mock.Anything, "john", "", DefaultPageSize).Return(&models.UserSearchPageModel{
+		Results: []*models.UserSearchResult{
+			{User: &models.UserModel{ID: "1"}, Score: 0.9},
+			{User: &models.UserModel{ID: "2", DeletedAt: timestamppb.Now()}, Score: 0.8},
+		},
+		NextCursor: "offset=2",
+	}, nil)
+	mockService.On("SearchUsers", mock.Anything, "john", "offset=2", DefaultPageSize).Return(&models.UserSearchPageModel{}, nil)
+
+	server := newTestServer(t, mockService)
+
+	first, err := server.SearchUsers(context.Background(), &pb.SearchUsersRequest{Query: " john "})
+	assert.NoError(t, err)
+	if assert.Len(t, first.Results, 1) {
+		assert.Equal(t, "1", first.Results[0].User.Id)
+		assert.Equal(t, 0.9, first.Results[0].Score)
+	}
+
+	second, err := server.SearchUsers(context.Background(), &pb.SearchUsersRequest{Query: "john", PageToken: first.NextPageToken})
+	assert.NoError(t, err)
+	assert.Empty(t, second.Results)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_SearchUsers_InvalidQuery(t *testing.T) {
+	server := newTestServer(t, &MockUserService{})
+
+	for _, query := range []string{"", "   ", "@@@", strings.Repeat("a", MaxSearchQueryLength+1)} {
+		_, err := server.SearchUsers(context.Background(), &pb.SearchUsersRequest{Query: query})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "query %q", query)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance([]rune("john"), []rune("john")))
+	assert.Equal(t, 1, editDistance([]rune("jhon"), []rune("john")))
+	assert.Equal(t, 1, editDistance([]rune("jon"), []rune("john")))
+	assert.Equal(t, 3, editDistance([]rune("kitten"), []rune("sitting")))
+}
+
+// MockBatchUserService additionally implements BatchGetter
+type MockBatchUserService struct {
+	MockUserService
+}
+
+func (m *MockBatchUserService) GetUsersByIDs(ctx context.Context, ids []string) (map[string]*models.UserModel, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*models.UserModel), args.Error(1)
+}
+
+func TestUserServiceServer_BatchGetUsers_Fallback(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("GetUserByID", mock.Anything, "1").Return(&models.UserModel{ID: "1"}, nil).Once()
+	mockService.On("GetUserByID", mock.Anything, "2").Return(nil, fmt.Errorf("user 2: %w", models.ErrNotFound)).Once()
+	mockService.On("GetUserByID", mock.Anything, "3").Return(&models.UserModel{ID: "3"}, nil).Once()
+	mockService.On("GetUserByID", mock.Anything, "4").Return(nil, errors.New("user not found")).Once()
+
+	server := newTestServer(t, mockService, WithBatchParallelism(2))
+
+	resp, err := server.BatchGetUsers(context.Background(), &pb.BatchGetUsersRequest{
+		Ids: []string{"1", "2", "3", "1", "4", "3"},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Users, 2)
+	assert.Equal(t, "1", resp.Users["1"].Id)
+	assert.Equal(t, "3", resp.Users["3"].Id)
+	assert.Equal(t, []string{"2", "4"}, resp.MissingIds)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_BatchGetUsers_FallbackError(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("GetUserByID", mock.Anything, mock.Anything).Return(nil, errors.New("connection reset"))
+
+	server := newTestServer(t, mockService)
+
+	_, err := server.BatchGetUsers(context.Background(), &pb.BatchGetUsersRequest{Ids: []string{"1", "2"}})
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestUserServiceServer_BatchGetUsers_BatchGetter(t *testing.T) {
+	mockService := &MockBatchUserService{}
+	mockService.On("GetUsersByIDs", mock.Anything, []string{"1", "2"}).Return(map[string]*models.UserModel{
+		"2": {ID: "2"},
+	}, nil)
+
+	server := newTestServer(t, mockService)
+
+	resp, err := server.BatchGetUsers(context.Background(), &pb.BatchGetUsersRequest{Ids: []string{"1", "2", "2"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2", resp.Users["2"].Id)
+	assert.Equal(t, []string{"1"}, resp.MissingIds)
+	mockService.AssertExpectations(t)
+}
+
+func TestUserServiceServer_BatchGetUsers_Deleted(t *testing.T) {
+	mockService := &MockBatchUserService{}
+	mockService.On("GetUsersByIDs", mock.Anything, []string{"1", "2"}).Return(map[string]*models.UserModel{
+		"1": {ID: "1"},
+		"2": {ID: "2", DeletedAt: timestamppb.Now()},
+	}, nil)
+
+	server := newTestServer(t, mockService)
+
+	resp, err := server.BatchGetUsers(context.Background(), &pb.BatchGetUsersRequest{Ids: []string{"1", "2"}})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Users, 1)
+	assert.Equal(t, []string{"2"}, resp.MissingIds)
+
+	resp, err = server.BatchGetUsers(context.Background(), &pb.BatchGetUsersRequest{Ids: []string{"1", "2"}, IncludeDeleted: true})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Users, 2)
+	assert.Empty(t, resp.MissingIds)
+}
+
+func TestUserServiceServer_BatchGetUsers_Validation(t *testing.T) {
+	server := newTestServer(t, &MockUserService{}, WithMaxBatchSize(2))
+
+	for _, ids := range [][]string{nil, {"1", ""}, {"1", "2", "3"}} {
+		_, err := server.BatchGetUsers(context.Background(), &pb.BatchGetUsersRequest{Ids: ids})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "ids %v", ids)
+	}
+
+	// Duplicates do not count towards the limit
+	mockService := &MockBatchUserService{}
+	mockService.On("GetUsersByIDs", mock.Anything, []string{"1", "2"}).Return(map[string]*models.UserModel{}, nil)
+	server = newTestServer(t, mockService, WithMaxBatchSize(2))
+	_, err := server.BatchGetUsers(context.Background(), &pb.BatchGetUsersRequest{Ids: []string{"1", "2", "1"}})
+	assert.NoError(t, err)
+}
+
+// fakeExportStream collects the messages sent by ExportUsers
+type fakeExportStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	responses []*pb.ExportUsersResponse
+	failAfter int
+}
+
+func (f *fakeExportStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeExportStream) Send(resp *pb.ExportUsersResponse) error {
+	if f.failAfter > 0 && len(f.responses) == f.failAfter {
+		return status.Error(codes.Unavailable, "stream broken")
+	}
+	f.responses = append(f.responses, resp)
+	return nil
+}
+
+func (f *fakeExportStream) ids() []string {
+	ids := []string{}
+	for _, resp := range f.responses {
+		ids = append(ids, resp.User.Id)
+	}
+	return ids
+}
+
+func TestUserServiceServer_ExportUsers_Resume(t *testing.T) {
+	mockService := &MockUserService{}
+	mockService.On("ListUsers", mock.Anything, int64(1), int64(2)).Return(&models.PaginatedUsersModel{
+		Users:      []*models.UserModel{{ID: "1"}, {ID: "2", DeletedAt: timestamppb.Now()}},
+		TotalPages: 2,
+	}, nil)
+	mockService.On("ListUsers", mock.Anything, int64(2), int64(2)).Return(&models.PaginatedUsersModel{
+		Users:      []*models.UserModel{{ID: "3"}, {ID: "4"}},
+		TotalPages: 2,
+	}, nil)
+
+	server := newTestServer(t, mockService, WithMaxPageSize(2))
+
+	broken := &fakeExportStream{ctx: context.Background(), failAfter: 2}
+	err := server.ExportUsers(&pb.ExportUsersRequest{}, broken)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, []string{"1", "3"}, broken.ids())
+
+	resumed := &fakeExportStream{ctx: context.Background()}
+	err = server.ExportUsers(&pb.ExportUsersRequest{ResumeToken: broken.responses[1].ResumeToken}, resumed)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"4"}, resumed.ids())
+
+	_, err = server.pageTokens.decode(resumed.responses[0].ResumeToken)
+	assert.NoError(t, err)
+}
+
+func TestUserServiceServer_ExportUsers_InvalidResumeToken(t *testing.T) {
+	server := newTestServer(t, &MockUserService{})
+
+	err := server.ExportUsers(&pb.ExportUsersRequest{ResumeToken: "forged"}, &fakeExportStream{ctx: context.Background()})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// MockExportingUserService additionally implements UserExporter
+type MockExportingUserService struct {
+	MockUserService
+	users []*models.UserModel
+}
+
+func (m *MockExportingUserService) ExportUsers(ctx context.Context, query models.UserQuery, cursor string, emit func(*models.UserModel, string) error) error {
+	start := 0
+	if cursor != "" {
+		start, _ = strconv.Atoi(cursor)
+	}
+	for i := start; i < len(m.users); i++ {
+		if err := emit(m.users[i], strconv.Itoa(i+1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestUserServiceServer_ExportUsers_Exporter(t *testing.T) {
+	mockService := &MockExportingUserService{
+		users: []*models.UserModel{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+	}
+
+	server := newTestServer(t, mockService)
+
+	first := &fakeExportStream{ctx: context.Background(), failAfter: 1}
+	_ = server.ExportUsers(&pb.ExportUsersRequest{Filter: &pb.UserFilter{IncludeDeleted: true}}, first)
+
+	resumed := &fakeExportStream{ctx: context.Background()}
+	err := server.ExportUsers(&pb.ExportUsersRequest{
+		Filter:      &pb.UserFilter{IncludeDeleted: true},
+		ResumeToken: first.responses[0].ResumeToken,
+	}, resumed)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2", "3"}, resumed.ids())
+
+	// Resume tokens are bound to the filter
+	err = server.ExportUsers(&pb.ExportUsersRequest{ResumeToken: first.responses[0].ResumeToken}, &fakeExportStream{ctx: context.Background()})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+const (
+	// DefaultWatchHistorySize is how many past events a Broadcaster keeps
+	// for watchers resuming from an earlier revision
+	DefaultWatchHistorySize = 1000
+	// DefaultWatchBufferSize is how many events a Broadcaster queues per
+	// watcher before dropping it as a slow consumer
+	DefaultWatchBufferSize = 64
+)
+
+// UserEventSource feeds the WatchUsers change feed. Adapters either
+// implement it themselves (e.g. on top of a database change stream) or
+// publish their changes to a Broadcaster passed with WithEventSource.
+//
+// Subscribe returns the events after afterRevision, followed by new events
+// as they happen; afterRevision 0 only returns new events. It fails with
+// models.ErrRevisionCompacted if the events after afterRevision are no
+// longer available. The subscription must end when ctx is done.
+type UserEventSource interface {
+	Subscribe(ctx context.Context, afterRevision int64) (Subscription, error)
+}
+
+// Subscription is a stream of user events in revision order
+type Subscription interface {
+	// Events is closed when the subscription ends
+	Events() <-chan models.UserEvent
+	// Err reports why Events was closed, e.g. models.ErrSlowConsumer, or nil
+	// if the subscription was closed by its owner
+	Err() error
+	// Close ends the subscription and releases its resources
+	Close()
+}
+
+// errBroadcasterClosed ends subscriptions when their Broadcaster shuts down,
+// telling watchers to reconnect to another replica
+var errBroadcasterClosed = status.Error(codes.Unavailable, "event source closed")
+
+// Broadcaster is an in-memory UserEventSource. The adapter calls Publish
+// after each successful change; every subscriber gets its own buffered
+// queue, and subscribers that let it fill up are dropped with
+// models.ErrSlowConsumer instead of holding back the others. The most
+// recent events are retained so that watchers can resume after a reconnect.
+//
+// Revisions start at 1 and are not persisted: after a restart watchers that
+// try to resume get models.ErrRevisionCompacted and must resynchronize.
+type Broadcaster struct {
+	mu          sync.Mutex
+	revision    int64
+	history     []models.UserEvent
+	historySize int
+	bufferSize  int
+	subscribers map[*subscription]struct{}
+	closed      bool
+	now         func() time.Time
+}
+
+// NewBroadcaster creates a Broadcaster that retains historySize events and
+// queues up to bufferSize events per subscriber. Non-positive values use
+// DefaultWatchHistorySize and DefaultWatchBufferSize.
+func NewBroadcaster(historySize, bufferSize int) *Broadcaster {
+	if historySize <= 0 {
+		historySize = DefaultWatchHistorySize
+	}
+	if bufferSize <= 0 {
+		bufferSize = DefaultWatchBufferSize
+	}
+
+	return &Broadcaster{
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*subscription]struct{}),
+		now:         time.Now,
+	}
+}
+
+// Publish records a change to user, assigns it the next revision and
+// delivers it to every subscriber. It never blocks on subscribers.
+func (b *Broadcaster) Publish(eventType models.UserEventType, user *models.UserModel) models.UserEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.revision++
+	event := models.UserEvent{
+		Type:       eventType,
+		User:       user,
+		Revision:   b.revision,
+		OccurredAt: b.now(),
+	}
+	if b.closed {
+		return event
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			b.remove(sub, models.ErrSlowConsumer)
+		}
+	}
+
+	return event
+}
+
+// Revision returns the revision of the latest published event
+func (b *Broadcaster) Revision() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.revision
+}
+
+// Subscribe implements UserEventSource
+func (b *Broadcaster) Subscribe(ctx context.Context, afterRevision int64) (Subscription, error) {
+	if afterRevision < 0 {
+		return nil, models.NewValidationError("after_revision", "must not be negative")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, errBroadcasterClosed
+	}
+
+	var replay []models.UserEvent
+	if afterRevision > 0 {
+		oldest := b.revision - int64(len(b.history)) + 1
+		if afterRevision > b.revision || afterRevision < oldest-1 {
+			return nil, models.ErrRevisionCompacted
+		}
+		replay = b.history[afterRevision-oldest+1:]
+	}
+
+	sub := &subscription{
+		broadcaster: b,
+		events:      make(chan models.UserEvent, b.bufferSize+len(replay)),
+	}
+	for _, event := range replay {
+		sub.events <- event
+	}
+	b.subscribers[sub] = struct{}{}
+	sub.stop = context.AfterFunc(ctx, sub.Close)
+
+	return sub, nil
+}
+
+// Close ends every subscription with codes.Unavailable and rejects new ones
+func (b *Broadcaster) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub, errBroadcasterClosed)
+	}
+}
+
+// remove ends sub with err; b.mu must be held
+func (b *Broadcaster) remove(sub *subscription, err error) {
+	if sub.done {
+		return
+	}
+	sub.done = true
+	sub.err = err
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
+
+// subscription is a Broadcaster subscriber; its state is guarded by the
+// broadcaster's mutex
+type subscription struct {
+	broadcaster *Broadcaster
+	events      chan models.UserEvent
+	err         error
+	done        bool
+	stop        func() bool
+}
+
+func (s *subscription) Events() <-chan models.UserEvent {
+	return s.events
+}
+
+func (s *subscription) Err() error {
+	s.broadcaster.mu.Lock()
+	defer s.broadcaster.mu.Unlock()
+	return s.err
+}
+
+func (s *subscription) Close() {
+	s.broadcaster.mu.Lock()
+	s.broadcaster.remove(s, nil)
+	s.broadcaster.mu.Unlock()
+
+	if s.stop != nil {
+		s.stop()
+	}
+}
+
+// ConvertUserEventToProto converts a domain user event to protobuf message
+func (c *ModelConverter) ConvertUserEventToProto(event models.UserEvent) *pb.UserEvent {
+	return &pb.UserEvent{
+		Type:       c.ConvertUserEventTypeToProto(event.Type),
+		User:       c.ConvertUserToProto(event.User),
+		Revision:   event.Revision,
+		OccurredAt: timestamppb.New(event.OccurredAt),
+	}
+}
+
+// ConvertUserEventTypeToProto converts domain event type to protobuf event type
+func (c *ModelConverter) ConvertUserEventTypeToProto(eventType models.UserEventType) pb.UserEventType {
+	switch eventType {
+	case models.UserEventCreated:
+		return pb.UserEventType_USER_EVENT_TYPE_CREATED
+	case models.UserEventUpdated:
+		return pb.UserEventType_USER_EVENT_TYPE_UPDATED
+	case models.UserEventDeleted:
+		return pb.UserEventType_USER_EVENT_TYPE_DELETED
+	case models.UserEventRoleChanged:
+		return pb.UserEventType_USER_EVENT_TYPE_ROLE_CHANGED
+	default:
+		return pb.UserEventType_USER_EVENT_TYPE_UNSPECIFIED
+	}
+}
+
+// parseEventTypes returns the set of event types to deliver, or nil for all
+func parseEventTypes(types []pb.UserEventType) (map[pb.UserEventType]bool, error) {
+	if len(types) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[pb.UserEventType]bool, len(types))
+	for _, t := range types {
+		if t == pb.UserEventType_USER_EVENT_TYPE_UNSPECIFIED || pb.UserEventType_name[int32(t)] == "" {
+			return nil, models.NewValidationError("types", "must only contain known event types")
+		}
+		result[t] = true
+	}
+	return result, nil
+}
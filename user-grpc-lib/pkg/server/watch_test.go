@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Kiraberos/grpc/user-grpc-lib/pkg/models"
+	pb "github.com/Kiraberos/grpc/user-grpc-lib/proto/user/v1"
+)
+
+// fakeWatchStream forwards the events sent by WatchUsers to a channel
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *pb.UserEvent
+}
+
+func newFakeWatchStream(ctx context.Context) *fakeWatchStream {
+	return &fakeWatchStream{ctx: ctx, events: make(chan *pb.UserEvent, 16)}
+}
+
+func (f *fakeWatchStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeWatchStream) Send(resp *pb.WatchUsersResponse) error {
+	f.events <- resp.Event
+	return nil
+}
+
+func receiveEvent(t *testing.T, events <-chan models.UserEvent) models.UserEvent {
+	t.Helper()
+
+	select {
+	case event, ok := <-events:
+		require.True(t, ok, "subscription ended")
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return models.UserEvent{}
+	}
+}
+
+func TestBroadcaster_Resume(t *testing.T) {
+	b := NewBroadcaster(2, 0)
+	b.Publish(models.UserEventCreated, &models.UserModel{ID: "1"})
+	b.Publish(models.UserEventUpdated, &models.UserModel{ID: "1"})
+	b.Publish(models.UserEventDeleted, &models.UserModel{ID: "1"})
+	assert.Equal(t, int64(3), b.Revision())
+
+	sub, err := b.Subscribe(context.Background(), 1)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	assert.Equal(t, int64(2), receiveEvent(t, sub.Events()).Revision)
+	assert.Equal(t, int64(3), receiveEvent(t, sub.Events()).Revision)
+
+	b.Publish(models.UserEventRoleChanged, &models.UserModel{ID: "1"})
+	event := receiveEvent(t, sub.Events())
+	assert.Equal(t, int64(4), event.Revision)
+	assert.Equal(t, models.UserEventRoleChanged, event.Type)
+}
+
+func TestBroadcaster_Compacted(t *testing.T) {
+	b := NewBroadcaster(2, 0)
+	for i := 0; i < 4; i++ {
+		b.Publish(models.UserEventUpdated, &models.UserModel{ID: "1"})
+	}
+
+	_, err := b.Subscribe(context.Background(), 1)
+	assert.ErrorIs(t, err, models.ErrRevisionCompacted)
+
+	// Revisions from before a restart are rejected the same way
+	_, err = b.Subscribe(context.Background(), 10)
+	assert.ErrorIs(t, err, models.ErrRevisionCompacted)
+
+	sub, err := b.Subscribe(context.Background(), 2)
+	require.NoError(t, err)
+	sub.Close()
+}
+
+func TestBroadcaster_SlowConsumer(t *testing.T) {
+	b := NewBroadcaster(0, 1)
+
+	slow, err := b.Subscribe(context.Background(), 0)
+	require.NoError(t, err)
+	fast, err := b.Subscribe(context.Background(), 0)
+	require.NoError(t, err)
+	defer fast.Close()
+
+	b.Publish(models.UserEventCreated, &models.UserModel{ID: "1"})
+	receiveEvent(t, fast.Events())
+	b.Publish(models.UserEventCreated, &models.UserModel{ID: "2"})
+	receiveEvent(t, fast.Events())
+
+	// The slow subscriber keeps the event it had room for, then ends
+	assert.Equal(t, int64(1), receiveEvent(t, slow.Events()).Revision)
+	_, ok := <-slow.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, slow.Err(), models.ErrSlowConsumer)
+}
+
+func TestBroadcaster_ContextDone(t *testing.T) {
+	b := NewBroadcaster(0, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub, err := b.Subscribe(ctx, 0)
+	require.NoError(t, err)
+	cancel()
+
+	select {
+	case _, ok := <-sub.Events():
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("subscription not closed")
+	}
+	assert.NoError(t, sub.Err())
+}
+
+func TestUserServiceServer_WatchUsers(t *testing.T) {
+	b := NewBroadcaster(0, 0)
+	server := newTestServer(t, &MockUserService{}, WithEventSource(b))
+	b.Publish(models.UserEventCreated, &models.UserModel{ID: "1", Role: models.RoleUser})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := newFakeWatchStream(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- server.WatchUsers(&pb.WatchUsersRequest{
+			AfterRevision: 0,
+			Types:         []pb.UserEventType{pb.UserEventType_USER_EVENT_TYPE_ROLE_CHANGED},
+		}, stream)
+	}()
+
+	// Wait for the subscription before publishing
+	require.Eventually(t, func() bool {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return len(b.subscribers) == 1
+	}, time.Second, time.Millisecond)
+
+	b.Publish(models.UserEventUpdated, &models.UserModel{ID: "1"})
+	b.Publish(models.UserEventRoleChanged, &models.UserModel{ID: "1", Role: models.RoleAdmin})
+
+	event := <-stream.events
+	assert.Equal(t, pb.UserEventType_USER_EVENT_TYPE_ROLE_CHANGED, event.Type)
+	assert.Equal(t, int64(3), event.Revision)
+	assert.Equal(t, pb.Role_ROLE_ADMIN, event.User.Role)
+
+	cancel()
+	assert.Equal(t, codes.Canceled, status.Code(<-done))
+}
+
+func TestUserServiceServer_WatchUsers_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		server       *UserServiceServer
+		req          *pb.WatchUsersRequest
+		expectedCode codes.Code
+	}{
+		{
+			name:         "no event source",
+			server:       newTestServer(t, &MockUserService{}),
+			req:          &pb.WatchUsersRequest{},
+			expectedCode: codes.Unimplemented,
+		},
+		{
+			name:         "unknown event type",
+			server:       newTestServer(t, &MockUserService{}, WithEventSource(NewBroadcaster(0, 0))),
+			req:          &pb.WatchUsersRequest{Types: []pb.UserEventType{pb.UserEventType_USER_EVENT_TYPE_UNSPECIFIED}},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "compacted revision",
+			server:       newTestServer(t, &MockUserService{}, WithEventSource(NewBroadcaster(0, 0))),
+			req:          &pb.WatchUsersRequest{AfterRevision: 5},
+			expectedCode: codes.OutOfRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.server.WatchUsers(tt.req, newFakeWatchStream(context.Background()))
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Claims are the claims of an access token. The registered claims follow
+// RFC 7519; Role, Tenant and Scopes are specific to this service.
+type Claims struct {
+	// ID is the unique token ID ("jti")
+	ID        string
+	Issuer    string
+	Subject   string
+	Audience  []string
+	IssuedAt  time.Time
+	NotBefore time.Time
+	ExpiresAt time.Time
+
+	Role   string
+	Tenant string
+	Scopes []string
+}
+
+// HasAudience reports whether aud is one of the token's audiences
+func (c *Claims) HasAudience(aud string) bool {
+	for _, a := range c.Audience {
+		if a == aud {
+			return true
+		}
+	}
+	return false
+}
+
+// claimsJSON is the wire form of Claims
+type claimsJSON struct {
+	ID        string      `json:"jti,omitempty"`
+	Issuer    string      `json:"iss,omitempty"`
+	Subject   string      `json:"sub,omitempty"`
+	Audience  audience    `json:"aud,omitempty"`
+	IssuedAt  numericDate `json:"iat,omitzero"`
+	NotBefore numericDate `json:"nbf,omitzero"`
+	ExpiresAt numericDate `json:"exp,omitzero"`
+	Role      string      `json:"role,omitempty"`
+	Tenant    string      `json:"tenant,omitempty"`
+	Scope     string      `json:"scope,omitempty"`
+}
+
+// MarshalJSON encodes the claims as a JWT payload
+func (c Claims) MarshalJSON() ([]byte, error) {
+	return json.Marshal(claimsJSON{
+		ID:        c.ID,
+		Issuer:    c.Issuer,
+		Subject:   c.Subject,
+		Audience:  c.Audience,
+		IssuedAt:  numericDate(c.IssuedAt),
+		NotBefore: numericDate(c.NotBefore),
+		ExpiresAt: numericDate(c.ExpiresAt),
+		Role:      c.Role,
+		Tenant:    c.Tenant,
+		Scope:     strings.Join(c.Scopes, " "),
+	})
+}
+
+// UnmarshalJSON decodes a JWT payload
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	var wire claimsJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	*c = Claims{
+		ID:        wire.ID,
+		Issuer:    wire.Issuer,
+		Subject:   wire.Subject,
+		Audience:  wire.Audience,
+		IssuedAt:  time.Time(wire.IssuedAt),
+		NotBefore: time.Time(wire.NotBefore),
+		ExpiresAt: time.Time(wire.ExpiresAt),
+		Role:      wire.Role,
+		Tenant:    wire.Tenant,
+		Scopes:    strings.Fields(wire.Scope),
+	}
+	return nil
+}
+
+// audience is a list of audiences, encoded as a single string when it has
+// one element as most JWT libraries do
+type audience []string
+
+func (a audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("aud must be a string or an array of strings")
+	}
+	*a = list
+	return nil
+}
+
+// numericDate is a time encoded as seconds since the Unix epoch. The zero
+// time is omitted.
+type numericDate time.Time
+
+func (d numericDate) IsZero() bool {
+	return time.Time(d).IsZero()
+}
+
+func (d numericDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Time(d).Unix())
+}
+
+func (d *numericDate) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return fmt.Errorf("numeric date must be a number")
+	}
+
+	whole, frac := math.Modf(seconds)
+	*d = numericDate(time.Unix(int64(whole), int64(frac*1e9)))
+	return nil
+}
@@ -0,0 +1,274 @@
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Signing algorithms
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+	EdDSA = "EdDSA"
+)
+
+const (
+	// MinHMACKeySize is the shortest HS256 secret accepted, in bytes
+	MinHMACKeySize = 32
+	// MinRSAKeyBits is the smallest RSA modulus accepted
+	MinRSAKeyBits = 2048
+)
+
+// Key is a signing or verification key identified by the "kid" header of
+// the tokens it signs
+type Key struct {
+	ID        string
+	Algorithm string
+	// private is the HS256 secret or the private key; nil for keys that
+	// can only verify
+	private any
+	// public is the public key; unused for HS256
+	public crypto.PublicKey
+}
+
+// NewKey creates a key from key material, picking the algorithm from its
+// type: a []byte secret (HS256), *rsa.PrivateKey (RS256), a P-256
+// *ecdsa.PrivateKey (ES256) or ed25519.PrivateKey (EdDSA). The matching
+// public key types create keys that can only verify.
+func NewKey(id string, material any) (*Key, error) {
+	if id == "" {
+		return nil, fmt.Errorf("key id is required")
+	}
+
+	key := &Key{ID: id}
+	switch k := material.(type) {
+	case []byte:
+		if len(k) < MinHMACKeySize {
+			return nil, fmt.Errorf("HS256 secret must be at least %d bytes", MinHMACKeySize)
+		}
+		key.Algorithm, key.private = HS256, append([]byte(nil), k...)
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < MinRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", MinRSAKeyBits)
+		}
+		key.Algorithm, key.private, key.public = RS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		if k.N.BitLen() < MinRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", MinRSAKeyBits)
+		}
+		key.Algorithm, key.public = RS256, k
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("ES256 requires a P-256 key")
+		}
+		key.Algorithm, key.private, key.public = ES256, k, &k.PublicKey
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("ES256 requires a P-256 key")
+		}
+		key.Algorithm, key.public = ES256, k
+	case ed25519.PrivateKey:
+		key.Algorithm, key.private, key.public = EdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Algorithm, key.public = EdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", material)
+	}
+	return key, nil
+}
+
+// GenerateKey creates a random key for algorithm
+func GenerateKey(id, algorithm string) (*Key, error) {
+	var material any
+	var err error
+
+	switch algorithm {
+	case HS256:
+		secret := make([]byte, MinHMACKeySize)
+		_, err = rand.Read(secret)
+		material = secret
+	case RS256:
+		material, err = rsa.GenerateKey(rand.Reader, MinRSAKeyBits)
+	case ES256:
+		material, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case EdDSA:
+		_, material, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s key: %w", algorithm, err)
+	}
+	return NewKey(id, material)
+}
+
+// ParseKeyPEM creates a key from a PEM encoded private key (PKCS #8,
+// PKCS #1 or SEC 1) or public key (PKIX)
+func ParseKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found for key %s", id)
+	}
+
+	var material any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		material, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		material, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		material, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		material, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q for key %s", block.Type, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key %s: %w", id, err)
+	}
+	return NewKey(id, material)
+}
+
+// CanSign reports whether the key holds private key material
+func (k *Key) CanSign() bool {
+	return k.private != nil
+}
+
+// PublicKey returns the public key, or nil for HS256 keys
+func (k *Key) PublicKey() crypto.PublicKey {
+	return k.public
+}
+
+// KeySource looks up verification keys by ID
+type KeySource interface {
+	VerificationKey(ctx context.Context, id string) (*Key, error)
+}
+
+// KeySet holds the keys of an issuer. One of them is active and signs new
+// tokens; the others only verify tokens signed before a rotation, until
+// they are removed. It is safe for concurrent use.
+type KeySet struct {
+	mu     sync.RWMutex
+	keys   map[string]*Key
+	active string
+}
+
+// NewKeySet creates a key set signing with active and also accepting
+// tokens signed by the other keys
+func NewKeySet(active *Key, others ...*Key) (*KeySet, error) {
+	s := &KeySet{keys: make(map[string]*Key)}
+	for _, key := range others {
+		if err := s.Add(key); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Rotate(active); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewVerifyingKeySet creates a key set that only verifies tokens, e.g.
+// with the public keys of another service
+func NewVerifyingKeySet(keys ...*Key) (*KeySet, error) {
+	s := &KeySet{keys: make(map[string]*Key)}
+	for _, key := range keys {
+		if err := s.Add(key); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Add adds a key that verifies tokens but does not sign them
+func (s *KeySet) Add(key *Key) error {
+	if key == nil || key.ID == "" {
+		return fmt.Errorf("key with an id is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.keys[key.ID]; ok {
+		return fmt.Errorf("key %s already exists", key.ID)
+	}
+	s.keys[key.ID] = key
+	return nil
+}
+
+// Rotate makes key the signing key. The previous signing key keeps
+// verifying tokens until it is removed, which should not happen before
+// the tokens it signed have expired.
+func (s *KeySet) Rotate(key *Key) error {
+	if key == nil || !key.CanSign() {
+		return fmt.Errorf("the active key must have private key material")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.keys[key.ID]; ok && existing != key {
+		return fmt.Errorf("key %s already exists", key.ID)
+	}
+	s.keys[key.ID] = key
+	s.active = key.ID
+	return nil
+}
+
+// Remove drops a key so that tokens it signed are no longer accepted. The
+// active key cannot be removed.
+func (s *KeySet) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id == s.active {
+		return fmt.Errorf("key %s is active", id)
+	}
+	delete(s.keys, id)
+	return nil
+}
+
+// Active returns the signing key
+func (s *KeySet) Active() *Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.keys[s.active]
+}
+
+// Keys returns every key, sorted by ID
+func (s *KeySet) Keys() []*Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]*Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys
+}
+
+// VerificationKey implements KeySource
+func (s *KeySet) VerificationKey(ctx context.Context, id string) (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	return key, nil
+}
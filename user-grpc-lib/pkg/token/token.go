@@ -0,0 +1,345 @@
+// Package token issues and verifies the JWT access tokens of the user
+// service. Adapters mint tokens with an Issuer, and servers and downstream
+// services check them with a Verifier.
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTTL is how long issued tokens are valid by default
+	DefaultTTL = 15 * time.Minute
+	// DefaultLeeway is the clock skew tolerated by default when checking
+	// the time-based claims
+	DefaultLeeway = time.Minute
+)
+
+// Verification errors, matched with errors.Is
+var (
+	ErrMalformed            = errors.New("malformed token")
+	ErrUnsupportedAlgorithm = errors.New("unsupported algorithm")
+	ErrUnknownKey           = errors.New("unknown signing key")
+	ErrInvalidSignature     = errors.New("invalid signature")
+	ErrExpired              = errors.New("token expired")
+	ErrNotYetValid          = errors.New("token not yet valid")
+	ErrInvalidIssuer        = errors.New("invalid issuer")
+	ErrInvalidAudience      = errors.New("invalid audience")
+)
+
+// Option configures an Issuer or a Verifier
+type Option func(*options)
+
+type options struct {
+	issuer   string
+	audience []string
+	ttl      time.Duration
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		ttl:    DefaultTTL,
+		leeway: DefaultLeeway,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithIssuer sets the "iss" claim of issued tokens. Verifiers reject
+// tokens from other issuers.
+func WithIssuer(issuer string) Option {
+	return func(o *options) {
+		o.issuer = issuer
+	}
+}
+
+// WithAudience sets the "aud" claim of issued tokens that do not set one.
+// Verifiers reject tokens not intended for any of these audiences.
+func WithAudience(audience ...string) Option {
+	return func(o *options) {
+		o.audience = audience
+	}
+}
+
+// WithTTL sets how long issued tokens are valid when they do not set an
+// expiry themselves
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		if ttl > 0 {
+			o.ttl = ttl
+		}
+	}
+}
+
+// WithLeeway sets the clock skew verifiers tolerate between the issuer and
+// themselves
+func WithLeeway(leeway time.Duration) Option {
+	return func(o *options) {
+		if leeway >= 0 {
+			o.leeway = leeway
+		}
+	}
+}
+
+// WithClock sets the function returning the current time
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// header is the JOSE header of a token
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// Issuer signs tokens with the active key of a KeySet
+type Issuer struct {
+	keys *KeySet
+	opts options
+}
+
+// NewIssuer creates an issuer signing with keys
+func NewIssuer(keys *KeySet, opts ...Option) *Issuer {
+	return &Issuer{keys: keys, opts: newOptions(opts)}
+}
+
+// Issue signs claims and returns the token together with the claims as
+// signed. Unset ID, Issuer, Audience, IssuedAt and ExpiresAt claims are
+// filled in from the issuer's options.
+func (i *Issuer) Issue(claims Claims) (string, Claims, error) {
+	now := i.opts.now().Truncate(time.Second)
+
+	if claims.ID == "" {
+		id, err := newTokenID()
+		if err != nil {
+			return "", claims, err
+		}
+		claims.ID = id
+	}
+	if claims.Issuer == "" {
+		claims.Issuer = i.opts.issuer
+	}
+	if len(claims.Audience) == 0 {
+		claims.Audience = i.opts.audience
+	}
+	if claims.IssuedAt.IsZero() {
+		claims.IssuedAt = now
+	}
+	if claims.ExpiresAt.IsZero() {
+		claims.ExpiresAt = now.Add(i.opts.ttl)
+	}
+
+	key := i.keys.Active()
+	token, err := sign(key, claims)
+	if err != nil {
+		return "", claims, err
+	}
+	return token, claims, nil
+}
+
+// Verifier checks tokens against the keys of a KeySource
+type Verifier struct {
+	keys KeySource
+	opts options
+}
+
+// NewVerifier creates a verifier accepting tokens signed by keys
+func NewVerifier(keys KeySource, opts ...Option) *Verifier {
+	return &Verifier{keys: keys, opts: newOptions(opts)}
+}
+
+// Verify checks the signature and claims of token and returns the claims.
+// The "alg" header must match the algorithm of the key named by "kid".
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected 3 segments", ErrMalformed)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrMalformed, err)
+	}
+	if h.KeyID == "" {
+		return nil, fmt.Errorf("%w: missing kid", ErrMalformed)
+	}
+
+	key, err := v.keys.VerificationKey(ctx, h.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if h.Algorithm != key.Algorithm {
+		return nil, fmt.Errorf("%w: %s for a %s key", ErrUnsupportedAlgorithm, h.Algorithm, key.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %v", ErrMalformed, err)
+	}
+	if err := verifySignature(key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrMalformed, err)
+	}
+	if err := v.checkClaims(&claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// checkClaims validates the time-based claims, issuer and audience
+func (v *Verifier) checkClaims(claims *Claims) error {
+	now := v.opts.now()
+
+	if claims.ExpiresAt.IsZero() {
+		return fmt.Errorf("%w: missing exp", ErrMalformed)
+	}
+	if !now.Before(claims.ExpiresAt.Add(v.opts.leeway)) {
+		return ErrExpired
+	}
+	if !claims.NotBefore.IsZero() && now.Add(v.opts.leeway).Before(claims.NotBefore) {
+		return ErrNotYetValid
+	}
+	if !claims.IssuedAt.IsZero() && now.Add(v.opts.leeway).Before(claims.IssuedAt) {
+		return ErrNotYetValid
+	}
+
+	if v.opts.issuer != "" && claims.Issuer != v.opts.issuer {
+		return fmt.Errorf("%w: %q", ErrInvalidIssuer, claims.Issuer)
+	}
+	if len(v.opts.audience) > 0 {
+		for _, aud := range v.opts.audience {
+			if claims.HasAudience(aud) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %v", ErrInvalidAudience, claims.Audience)
+	}
+	return nil
+}
+
+// sign encodes and signs claims with key
+func sign(key *Key, claims Claims) (string, error) {
+	if key == nil || !key.CanSign() {
+		return "", fmt.Errorf("no signing key")
+	}
+
+	headerJSON, err := json.Marshal(header{Algorithm: key.Algorithm, Type: "JWT", KeyID: key.ID})
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	signature, err := signBytes(key, []byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func signBytes(key *Key, input []byte) ([]byte, error) {
+	digest := sha256.Sum256(input)
+
+	switch k := key.private.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	case *rsa.PrivateKey:
+		return rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		// JWS uses the fixed-size R || S encoding rather than ASN.1
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+		return signature, nil
+	case ed25519.PrivateKey:
+		return ed25519.Sign(k, input), nil
+	default:
+		return nil, fmt.Errorf("%w: key type %T", ErrUnsupportedAlgorithm, key.private)
+	}
+}
+
+func verifySignature(key *Key, input, signature []byte) error {
+	digest := sha256.Sum256(input)
+
+	valid := false
+	switch key.Algorithm {
+	case HS256:
+		secret, _ := key.private.([]byte)
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(input)
+		valid = len(secret) > 0 && hmac.Equal(mac.Sum(nil), signature)
+	case RS256:
+		pub, _ := key.public.(*rsa.PublicKey)
+		valid = pub != nil && rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature) == nil
+	case ES256:
+		pub, _ := key.public.(*ecdsa.PublicKey)
+		if pub != nil && len(signature) == 64 {
+			r := new(big.Int).SetBytes(signature[:32])
+			s := new(big.Int).SetBytes(signature[32:])
+			valid = ecdsa.Verify(pub, digest[:], r, s)
+		}
+	case EdDSA:
+		pub, _ := key.public.(ed25519.PublicKey)
+		valid = pub != nil && ed25519.Verify(pub, input, signature)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, key.Algorithm)
+	}
+
+	if !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// newTokenID returns a random "jti"
+func newTokenID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
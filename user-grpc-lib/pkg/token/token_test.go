@@ -0,0 +1,269 @@
+package token
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeySet(t *testing.T, id, algorithm string) *KeySet {
+	t.Helper()
+
+	key, err := GenerateKey(id, algorithm)
+	require.NoError(t, err)
+	keys, err := NewKeySet(key)
+	require.NoError(t, err)
+	return keys
+}
+
+func TestIssuer_RoundTrip(t *testing.T) {
+	for _, algorithm := range []string{HS256, RS256, ES256, EdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			keys := newTestKeySet(t, "k1", algorithm)
+			opts := []Option{WithIssuer("users"), WithAudience("api")}
+
+			raw, issued, err := NewIssuer(keys, opts...).Issue(Claims{
+				Subject: "123",
+				Role:    "admin",
+				Tenant:  "acme",
+				Scopes:  []string{"users:read", "users:write"},
+			})
+			require.NoError(t, err)
+			assert.NotEmpty(t, issued.ID)
+			assert.Equal(t, issued.IssuedAt.Add(DefaultTTL), issued.ExpiresAt)
+
+			claims, err := NewVerifier(keys, opts...).Verify(context.Background(), raw)
+			require.NoError(t, err)
+			assert.Equal(t, issued.ID, claims.ID)
+			assert.Equal(t, "users", claims.Issuer)
+			assert.Equal(t, "123", claims.Subject)
+			assert.Equal(t, []string{"api"}, claims.Audience)
+			assert.Equal(t, "admin", claims.Role)
+			assert.Equal(t, "acme", claims.Tenant)
+			assert.Equal(t, []string{"users:read", "users:write"}, claims.Scopes)
+			assert.True(t, issued.ExpiresAt.Equal(claims.ExpiresAt))
+		})
+	}
+}
+
+func TestVerifier_Rejects(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	keys := newTestKeySet(t, "k1", ES256)
+	issuer := NewIssuer(keys, WithIssuer("users"), WithAudience("api"), WithClock(func() time.Time { return now }))
+
+	issue := func(claims Claims) string {
+		raw, _, err := issuer.Issue(claims)
+		require.NoError(t, err)
+		return raw
+	}
+	valid := issue(Claims{Subject: "123"})
+
+	tests := []struct {
+		name     string
+		token    string
+		opts     []Option
+		expected error
+	}{
+		{name: "expired", token: valid, opts: []Option{WithClock(func() time.Time { return now.Add(DefaultTTL + 2*time.Minute) })}, expected: ErrExpired},
+		{name: "not yet valid", token: issue(Claims{Subject: "123", NotBefore: now.Add(time.Hour)}), expected: ErrNotYetValid},
+		{name: "wrong issuer", token: valid, opts: []Option{WithIssuer("other")}, expected: ErrInvalidIssuer},
+		{name: "wrong audience", token: valid, opts: []Option{WithAudience("billing")}, expected: ErrInvalidAudience},
+		{name: "tampered claims", token: tamper(t, valid), expected: ErrInvalidSignature},
+		{name: "truncated", token: valid[:strings.LastIndex(valid, ".")], expected: ErrMalformed},
+		{name: "unknown key", token: reheader(t, valid, map[string]string{"alg": ES256, "kid": "k2"}), expected: ErrUnknownKey},
+		{name: "algorithm mismatch", token: reheader(t, valid, map[string]string{"alg": HS256, "kid": "k1"}), expected: ErrUnsupportedAlgorithm},
+		{name: "alg none", token: reheader(t, valid, map[string]string{"alg": "none", "kid": "k1"}), expected: ErrUnsupportedAlgorithm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithClock(func() time.Time { return now })}, tt.opts...)
+			_, err := NewVerifier(keys, opts...).Verify(context.Background(), tt.token)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestVerifier_Leeway(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	keys := newTestKeySet(t, "k1", HS256)
+
+	// The issuer's clock runs 30s ahead of the verifier's
+	raw, _, err := NewIssuer(keys, WithClock(func() time.Time { return now.Add(30 * time.Second) })).Issue(Claims{Subject: "123"})
+	require.NoError(t, err)
+
+	_, err = NewVerifier(keys, WithClock(func() time.Time { return now })).Verify(context.Background(), raw)
+	assert.NoError(t, err)
+
+	_, err = NewVerifier(keys, WithLeeway(0), WithClock(func() time.Time { return now })).Verify(context.Background(), raw)
+	assert.ErrorIs(t, err, ErrNotYetValid)
+
+	expiry := now.Add(30*time.Second + DefaultTTL)
+	_, err = NewVerifier(keys, WithClock(func() time.Time { return expiry.Add(30 * time.Second) })).Verify(context.Background(), raw)
+	assert.NoError(t, err)
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	keys := newTestKeySet(t, "k1", EdDSA)
+	issuer := NewIssuer(keys)
+	verifier := NewVerifier(keys)
+
+	old, _, err := issuer.Issue(Claims{Subject: "123"})
+	require.NoError(t, err)
+
+	next, err := GenerateKey("k2", RS256)
+	require.NoError(t, err)
+	require.NoError(t, keys.Rotate(next))
+	assert.Equal(t, "k2", keys.Active().ID)
+
+	fresh, _, err := issuer.Issue(Claims{Subject: "123"})
+	require.NoError(t, err)
+	assert.Equal(t, RS256, headerOf(t, fresh)["alg"])
+
+	_, err = verifier.Verify(context.Background(), old)
+	assert.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), fresh)
+	assert.NoError(t, err)
+
+	assert.Error(t, keys.Remove("k2"), "the active key cannot be removed")
+	require.NoError(t, keys.Remove("k1"))
+	_, err = verifier.Verify(context.Background(), old)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestParseKeyPEM(t *testing.T) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+
+	signing, err := ParseKeyPEM("k1", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+	require.NoError(t, err)
+	assert.Equal(t, ES256, signing.Algorithm)
+	assert.True(t, signing.CanSign())
+
+	verifying, err := ParseKeyPEM("k1", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	require.NoError(t, err)
+	assert.False(t, verifying.CanSign())
+
+	signingKeys, err := NewKeySet(signing)
+	require.NoError(t, err)
+	raw, _, err := NewIssuer(signingKeys).Issue(Claims{Subject: "123"})
+	require.NoError(t, err)
+
+	verifyingKeys, err := NewVerifyingKeySet(verifying)
+	require.NoError(t, err)
+	_, err = NewVerifier(verifyingKeys).Verify(context.Background(), raw)
+	assert.NoError(t, err)
+
+	_, err = NewKeySet(verifying)
+	assert.Error(t, err, "public keys cannot sign")
+}
+
+func TestNewKey_RejectsWeakKeys(t *testing.T) {
+	_, err := NewKey("k1", []byte("short"))
+	assert.Error(t, err)
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	_, err = NewKey("k1", p384)
+	assert.Error(t, err)
+}
+
+func TestClaims_AudienceEncoding(t *testing.T) {
+	var claims Claims
+	require.NoError(t, json.Unmarshal([]byte(`{"aud":"api","exp":1700000000.5}`), &claims))
+	assert.Equal(t, []string{"api"}, claims.Audience)
+	assert.Equal(t, int64(1700000000), claims.ExpiresAt.Unix())
+
+	require.NoError(t, json.Unmarshal([]byte(`{"aud":["api","billing"]}`), &claims))
+	assert.True(t, claims.HasAudience("billing"))
+
+	data, err := json.Marshal(Claims{Subject: "123", Audience: []string{"api"}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sub":"123","aud":"api"}`, string(data))
+}
+
+// headerOf decodes the JOSE header of a token
+func headerOf(t *testing.T, raw string) map[string]string {
+	t.Helper()
+
+	data, err := base64.RawURLEncoding.DecodeString(strings.Split(raw, ".")[0])
+	require.NoError(t, err)
+	var h map[string]string
+	require.NoError(t, json.Unmarshal(data, &h))
+	return h
+}
+
+// reheader replaces the header of a token, keeping its payload and signature
+func reheader(t *testing.T, raw string, h map[string]string) string {
+	t.Helper()
+
+	data, err := json.Marshal(h)
+	require.NoError(t, err)
+	parts := strings.Split(raw, ".")
+	return base64.RawURLEncoding.EncodeToString(data) + "." + parts[1] + "." + parts[2]
+}
+
+// tamper changes the subject of a token without re-signing it
+func tamper(t *testing.T, raw string) string {
+	t.Helper()
+
+	parts := strings.Split(raw, ".")
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	data = []byte(strings.Replace(string(data), `"sub":"123"`, `"sub":"admin"`, 1))
+	return parts[0] + "." + base64.RawURLEncoding.EncodeToString(data) + "." + parts[2]
+}
+
+func TestJWK_RoundTrip(t *testing.T) {
+	for _, algorithm := range []string{RS256, ES256, EdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			keys := newTestKeySet(t, "k1", algorithm)
+			raw, _, err := NewIssuer(keys).Issue(Claims{Subject: "123"})
+			require.NoError(t, err)
+
+			jwks := keys.PublicJWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, "k1", jwks.Keys[0].KeyID)
+			assert.Equal(t, algorithm, jwks.Keys[0].Algorithm)
+
+			// Through JSON, as a JWKS endpoint would serve it
+			data, err := json.Marshal(jwks)
+			require.NoError(t, err)
+			var decoded JWKS
+			require.NoError(t, json.Unmarshal(data, &decoded))
+
+			key, err := ParseJWK(decoded.Keys[0])
+			require.NoError(t, err)
+			assert.False(t, key.CanSign())
+
+			public, err := NewVerifyingKeySet(key)
+			require.NoError(t, err)
+			_, err = NewVerifier(public).Verify(context.Background(), raw)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestKeySet_PublicJWKSOmitsSecrets(t *testing.T) {
+	keys := newTestKeySet(t, "k1", HS256)
+	assert.Empty(t, keys.PublicJWKS().Keys)
+
+	_, err := ParseJWK(JWK{KeyType: "oct", KeyID: "k1"})
+	assert.Error(t, err)
+}